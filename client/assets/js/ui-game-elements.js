@@ -74,7 +74,12 @@ function drawBoard() {
                 });
 
                 let characterToDisplay;
-                if (tile.Letter.Character == WILDCARD_CHARACTER.charCodeAt()) {
+                if (tile.Letter.DesignatedCharacter) {
+                    // Wildcard tiles keep their identity on the board
+                    // and carry the letter they stand for separately
+                    characterToDisplay = String.fromCharCode(tile.Letter.DesignatedCharacter).toUpperCase();
+                    tileDiv.attr('data-isWildcardTile', true);
+                } else if (tile.Letter.Character == WILDCARD_CHARACTER.charCodeAt()) {
                     characterToDisplay = '<i class="fa fa-star-o" aria-hidden="true"></i>';
                 } else {
                     characterToDisplay = String.fromCharCode(tile.Letter.Character).toUpperCase();
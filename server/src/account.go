@@ -0,0 +1,267 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"gole/golelibs"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// File in which all accounts and their sessions are stored
+var ACCOUNTS_STORAGE_FILE = "gole_accounts.gob"
+
+var MIN_USERNAME_LENGTH = 3
+var MAX_USERNAME_LENGTH = 32
+var MIN_PASSWORD_LENGTH = 8
+
+// Time after which a session token issued at login is no longer accepted
+var ACCOUNT_SESSION_LIFETIME = 30 * 24 * time.Hour
+
+type Account struct {
+	Id           string
+	Username     string
+	PasswordHash []byte `json:"-"`
+	CreatedAt    time.Time
+
+	// One of the HAND_SORT_MODES the hand is arranged in after every
+	// refill, copied onto the account's seat in every new game
+	PreferredHandSortMode string
+}
+
+type AccountSession struct {
+	Token     string
+	AccountId string
+	ExpiresAt time.Time
+}
+
+type accountStore struct {
+	Accounts []*Account
+
+	// Sessions by their token
+	Sessions map[string]AccountSession
+}
+
+var accounts = accountStore{Sessions: make(map[string]AccountSession)}
+var accountsMutex sync.Mutex
+
+func saveAccounts() error {
+	// Persist all accounts and sessions to the ACCOUNTS_STORAGE_FILE
+	// Requires:
+	// - The accountsMutex to be held by the caller
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+	return writeGobFile(ACCOUNTS_STORAGE_FILE, accounts)
+}
+
+func LoadAccounts() error {
+	// Restore all accounts and sessions from the ACCOUNTS_STORAGE_FILE
+
+	accountsMutex.Lock()
+	defer accountsMutex.Unlock()
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+
+	var storedAccounts accountStore
+	hasStoredAccounts, err := readGobFile(ACCOUNTS_STORAGE_FILE, &storedAccounts)
+	if err != nil || !hasStoredAccounts {
+		return err
+	}
+	if storedAccounts.Sessions == nil {
+		storedAccounts.Sessions = make(map[string]AccountSession)
+	}
+
+	accounts = storedAccounts
+	log.Printf("Loaded %d accounts from %s", len(accounts.Accounts), ACCOUNTS_STORAGE_FILE)
+	return nil
+}
+
+func isValidUsername(username string) bool {
+	// Usernames consist of ascii letters, digits, dashes and underscores
+	if utf8.RuneCountInString(username) < MIN_USERNAME_LENGTH ||
+		utf8.RuneCountInString(username) > MAX_USERNAME_LENGTH {
+		return false
+	}
+	for _, character := range username {
+		isLetter := (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z')
+		isDigit := character >= '0' && character <= '9'
+		if !isLetter && !isDigit && character != '-' && character != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+func RegisterAccount(username string, password string) (Account, error) {
+	// Create a new account
+	// Requires:
+	// - A username that is not taken yet (case insensitive) of
+	//   MIN_USERNAME_LENGTH to MAX_USERNAME_LENGTH letters, digits,
+	//   dashes or underscores
+	// - A password of at least MIN_PASSWORD_LENGTH characters
+	// Guarantees:
+	// - The password is only stored as a bcrypt hash
+	// - Return the new account
+	// - Return an error if any of the requirements is not met
+
+	username = strings.TrimSpace(username)
+	if !isValidUsername(username) {
+		return Account{}, errors.New(fmt.Sprintf(
+			"Invalid username. Use %d to %d letters, digits, dashes or underscores.",
+			MIN_USERNAME_LENGTH, MAX_USERNAME_LENGTH))
+	}
+
+	if utf8.RuneCountInString(password) < MIN_PASSWORD_LENGTH {
+		return Account{}, errors.New(fmt.Sprintf(
+			"Password too short. At least %d characters are required.", MIN_PASSWORD_LENGTH))
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return Account{}, err
+	}
+
+	accountsMutex.Lock()
+	defer accountsMutex.Unlock()
+
+	for _, existingAccount := range accounts.Accounts {
+		if strings.EqualFold(existingAccount.Username, username) {
+			return Account{}, errors.New("The username " + username + " is already taken.")
+		}
+	}
+
+	account := &Account{
+		Id:           golelibs.GetNewUUID(),
+		Username:     username,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now().UTC(),
+	}
+	accounts.Accounts = append(accounts.Accounts, account)
+
+	err = saveAccounts()
+	if err != nil {
+		return Account{}, err
+	}
+
+	log.Printf("Registered account %s (%s)", account.Username, account.Id)
+	return *account, nil
+}
+
+func Login(username string, password string) (AccountSession, error) {
+	// Check the credentials of an account and open a new session
+	// Guarantees:
+	// - Return a session whose token authenticates the account
+	//   until the session expires
+	// - Return the same error for unknown usernames and wrong passwords
+
+	accountsMutex.Lock()
+	defer accountsMutex.Unlock()
+
+	invalidCredentialsErr := errors.New("Invalid username or password.")
+
+	var account *Account
+	for _, existingAccount := range accounts.Accounts {
+		if strings.EqualFold(existingAccount.Username, strings.TrimSpace(username)) {
+			account = existingAccount
+		}
+	}
+	if account == nil {
+		return AccountSession{}, invalidCredentialsErr
+	}
+
+	err := bcrypt.CompareHashAndPassword(account.PasswordHash, []byte(password))
+	if err != nil {
+		return AccountSession{}, invalidCredentialsErr
+	}
+
+	now := time.Now().UTC()
+
+	// Drop expired sessions while we're at it
+	for token, session := range accounts.Sessions {
+		if now.After(session.ExpiresAt) {
+			delete(accounts.Sessions, token)
+		}
+	}
+
+	session := AccountSession{
+		Token:     golelibs.GetNewSecretToken(),
+		AccountId: account.Id,
+		ExpiresAt: now.Add(ACCOUNT_SESSION_LIFETIME),
+	}
+	accounts.Sessions[session.Token] = session
+
+	err = saveAccounts()
+	if err != nil {
+		return AccountSession{}, err
+	}
+
+	return session, nil
+}
+
+func Logout(token string) error {
+	// End the session with the given token
+
+	accountsMutex.Lock()
+	defer accountsMutex.Unlock()
+
+	if _, exists := accounts.Sessions[token]; !exists {
+		return errors.New("No session with the given token.")
+	}
+	delete(accounts.Sessions, token)
+
+	return saveAccounts()
+}
+
+func GetAccountById(accountId string) (Account, error) {
+	accountsMutex.Lock()
+	defer accountsMutex.Unlock()
+
+	for _, account := range accounts.Accounts {
+		if account.Id == accountId {
+			return *account, nil
+		}
+	}
+	return Account{}, errors.New("Account with ID " + accountId + " does not exist.")
+}
+
+func SetPreferredHandSortMode(accountId string, mode string) error {
+	// Remember the mode in which the account's hand is arranged
+	// after every refill in the games it joins from now on,
+	// empty to keep the order of drawing
+
+	err := validatePreferredHandSortMode(mode)
+	if err != nil {
+		return err
+	}
+
+	accountsMutex.Lock()
+	defer accountsMutex.Unlock()
+
+	for _, account := range accounts.Accounts {
+		if account.Id == accountId {
+			account.PreferredHandSortMode = mode
+			return saveAccounts()
+		}
+	}
+	return errors.New("Account with ID " + accountId + " does not exist.")
+}
+
+func GetAccountBySessionToken(token string) (Account, error) {
+	// Return the account that the session with the given token belongs to
+	// Guarantees:
+	// - Return an error if there is no such session or if it has expired
+
+	accountsMutex.Lock()
+	session, exists := accounts.Sessions[token]
+	accountsMutex.Unlock()
+
+	if token == "" || !exists || time.Now().UTC().After(session.ExpiresAt) {
+		return Account{}, errors.New("No valid session for the given token.")
+	}
+
+	return GetAccountById(session.AccountId)
+}
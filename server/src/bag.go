@@ -0,0 +1,314 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	mathrand "math/rand"
+	"strings"
+)
+
+// Every game commits to the order of its letter set when it starts:
+// the hash of a secret salt and the initial order is published right away,
+// the salt, the order and every change of the letter set are revealed once
+// the game is over. Anyone can then check that no draw has been tampered with.
+
+// Number of random bytes in the salt of a commitment
+var BAG_SALT_LENGTH = 32
+
+// Ways in which the letter set changes
+const (
+	LETTER_SET_CHANGE_DRAW   = "draw"
+	LETTER_SET_CHANGE_RETURN = "return"
+)
+
+type LetterSetChange struct {
+	Type   string
+	Letter Letter
+
+	// Number of letters that are drawn before this one, i.e. 0 for the
+	// next letter in the set. Only a director takes letters from elsewhere.
+	Position int
+}
+
+type BagProof struct {
+	// SHA-256 of the salt followed by the initial order, see getBagOrderString
+	Commitment string
+
+	// Empty until the game is over
+	Salt string
+
+	// The letters in the order in which they would be drawn
+	InitialOrder []Letter
+	Changes      []LetterSetChange
+
+	// The seed of a seeded game, see GameOptions
+	IsSeeded bool
+	Seed     int64
+}
+
+type BagVerification struct {
+	IsValid  bool
+	Problems []string
+
+	NumberOfDraws int
+
+	// Draws of letters that were not next in the set,
+	// e.g. letters handed back to a player by a director
+	NumberOfOutOfOrderDraws int
+}
+
+func getRandomIntn(n int) int {
+	// Return a random number in [0, n) from a cryptographically secure
+	// source so that the order of the letters cannot be predicted
+	randomNumber, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic("No secure random numbers available: " + err.Error())
+	}
+	return int(randomNumber.Int64())
+}
+
+func getRandomPermutation(n int, randomIntn func(int) int) []int {
+	// Return a random permutation of the numbers [0, n)
+	// drawn with the given source of random numbers in [0, n)
+	permutation := make([]int, n)
+	for idx := range permutation {
+		permutation[idx] = idx
+	}
+	for idx := n - 1; idx > 0; idx-- {
+		otherIdx := randomIntn(idx + 1)
+		permutation[idx], permutation[otherIdx] = permutation[otherIdx], permutation[idx]
+	}
+	return permutation
+}
+
+// Random numbers of a seeded game. Only the seed and the number of values
+// taken so far are stored, the source is set up again when needed,
+// e.g. after the game has been loaded from storage.
+type SeededRandom struct {
+	Seed           int64
+	NumberOfValues int
+
+	random *mathrand.Rand
+}
+
+// Counts the values taken from a source
+type countingSource struct {
+	source         mathrand.Source
+	numberOfValues *int
+}
+
+func (countingSource countingSource) Int63() int64 {
+	*countingSource.numberOfValues++
+	return countingSource.source.Int63()
+}
+
+func (countingSource countingSource) Seed(seed int64) {
+	countingSource.source.Seed(seed)
+}
+
+func NewSeededRandom(seed int64) *SeededRandom {
+	return &SeededRandom{Seed: seed}
+}
+
+func (seededRandom *SeededRandom) Intn(n int) int {
+	// Return a random number in [0, n) that only depends
+	// on the seed and the numbers returned before
+
+	if seededRandom.random == nil {
+		source := mathrand.NewSource(seededRandom.Seed)
+		for i := 0; i < seededRandom.NumberOfValues; i++ {
+			source.Int63()
+		}
+		seededRandom.random = mathrand.New(countingSource{source, &seededRandom.NumberOfValues})
+	}
+	return seededRandom.random.Intn(n)
+}
+
+func (game *Game) getRandomIntn(n int) int {
+	// Seeded games take their random numbers from their own source,
+	// all others from a cryptographically secure one
+	if game.Random != nil {
+		return game.Random.Intn(n)
+	}
+	return getRandomIntn(n)
+}
+
+func generateBagSalt() string {
+	salt := make([]byte, BAG_SALT_LENGTH)
+	_, err := rand.Read(salt)
+	if err != nil {
+		panic("No secure random numbers available: " + err.Error())
+	}
+	return hex.EncodeToString(salt)
+}
+
+func getDrawOrder(letterSet []Letter) []Letter {
+	// The letter set is drawn from its end
+	drawOrder := make([]Letter, len(letterSet))
+	for letterIdx, letter := range letterSet {
+		drawOrder[len(letterSet)-1-letterIdx] = letter
+	}
+	return drawOrder
+}
+
+func getBagOrderString(drawOrder []Letter) string {
+	// Return the letters as "<Id>:<Character>" in draw order, comma separated
+	letterStrings := make([]string, len(drawOrder))
+	for letterIdx, letter := range drawOrder {
+		letterStrings[letterIdx] = letter.Id + ":" + string(letter.Character)
+	}
+	return strings.Join(letterStrings, ",")
+}
+
+func GetBagCommitment(salt string, drawOrder []Letter) string {
+	hash := sha256.Sum256([]byte(salt + getBagOrderString(drawOrder)))
+	return hex.EncodeToString(hash[:])
+}
+
+func (game *Game) commitToLetterSet() {
+	// Commit to the current order of the letter set.
+	// Must be called before the first letter is drawn.
+
+	game.BagSalt = generateBagSalt()
+	game.InitialLetterSet = append([]Letter{}, game.LetterSet...)
+	game.BagCommitment = GetBagCommitment(game.BagSalt, getDrawOrder(game.InitialLetterSet))
+	game.LetterSetChanges = nil
+}
+
+func (game *Game) takeLetterFromSet(position int) (Letter, error) {
+	// Take the letter at the given position, counted in draw order,
+	// out of the letter set and record the draw
+
+	if position < 0 || position >= len(game.LetterSet) {
+		return Letter{}, errors.New("Cannot pop letter from set. Empty.")
+	}
+
+	letterIdx := len(game.LetterSet) - 1 - position
+	letter := game.LetterSet[letterIdx]
+	game.LetterSet = append(game.LetterSet[:letterIdx], game.LetterSet[letterIdx+1:]...)
+
+	game.LetterSetChanges = append(game.LetterSetChanges, LetterSetChange{
+		Type:     LETTER_SET_CHANGE_DRAW,
+		Letter:   letter,
+		Position: position,
+	})
+	return letter, nil
+}
+
+func (game *Game) returnLetterToSet(letter Letter, position int) {
+	// Put a letter back into the letter set so that the given number
+	// of letters is drawn before it, and record the return
+
+	letter.DesignatedCharacter = 0
+	letterIdx := len(game.LetterSet) - position
+	game.LetterSet = append(game.LetterSet, Letter{})
+	copy(game.LetterSet[letterIdx+1:], game.LetterSet[letterIdx:])
+	game.LetterSet[letterIdx] = letter
+
+	game.LetterSetChanges = append(game.LetterSetChanges, LetterSetChange{
+		Type:     LETTER_SET_CHANGE_RETURN,
+		Letter:   letter,
+		Position: position,
+	})
+}
+
+func (game *Game) GetBagProof() BagProof {
+	// Return the commitment to the letter set and,
+	// once the game is over, everything needed to verify it
+
+	bagProof := BagProof{Commitment: game.BagCommitment, IsSeeded: game.Options.IsSeeded}
+	if game.GameOver {
+		bagProof.Seed = game.Options.Seed
+		bagProof.Salt = game.BagSalt
+		bagProof.InitialOrder = getDrawOrder(game.InitialLetterSet)
+		bagProof.Changes = game.LetterSetChanges
+	}
+	return bagProof
+}
+
+func (game *Game) VerifyBag() (BagVerification, error) {
+	// Check that the letters of a finished game have been drawn
+	// as the game has committed to when it started
+	// Guarantees:
+	// - The revealed salt and initial order must match the commitment
+	// - Replaying the recorded changes on the initial order must draw
+	//   exactly the recorded letters and end with the letters left in the set
+	// - The letters drawn in the turns of the history must have been drawn
+	//   in the same order in the replay
+	// - Every violation is listed as a problem
+	// - Return an error if the game is not over or has been
+	//   started without a commitment
+
+	if !game.GameOver {
+		return BagVerification{}, errors.New("The letter set can only be verified once the game is over.")
+	}
+	if game.BagCommitment == "" {
+		return BagVerification{}, errors.New("The game has been started without a commitment to its letter set.")
+	}
+
+	bagProof := game.GetBagProof()
+	verification := BagVerification{}
+	addProblem := func(format string, arguments ...interface{}) {
+		verification.Problems = append(verification.Problems, fmt.Sprintf(format, arguments...))
+	}
+
+	if GetBagCommitment(bagProof.Salt, bagProof.InitialOrder) != bagProof.Commitment {
+		addProblem("The initial order of the letter set does not match the commitment.")
+	}
+
+	drawOrder := append([]Letter{}, bagProof.InitialOrder...)
+	var drawnLetterIds []string
+	for changeIdx, change := range bagProof.Changes {
+		switch change.Type {
+		case LETTER_SET_CHANGE_DRAW:
+			if change.Position >= len(drawOrder) || drawOrder[change.Position].Id != change.Letter.Id {
+				addProblem("Draw %d took letter %s which was not at position %d.",
+					changeIdx, change.Letter.Id, change.Position)
+				continue
+			}
+			drawOrder = append(drawOrder[:change.Position], drawOrder[change.Position+1:]...)
+			drawnLetterIds = append(drawnLetterIds, change.Letter.Id)
+			verification.NumberOfDraws++
+			if change.Position != 0 {
+				verification.NumberOfOutOfOrderDraws++
+			}
+		case LETTER_SET_CHANGE_RETURN:
+			if change.Position > len(drawOrder) {
+				addProblem("Letter %s was returned to position %d beyond the end of the letter set.",
+					change.Letter.Id, change.Position)
+				continue
+			}
+			drawOrder = append(drawOrder[:change.Position], append([]Letter{change.Letter}, drawOrder[change.Position:]...)...)
+		default:
+			addProblem("Unknown change %s of the letter set.", change.Type)
+		}
+	}
+
+	if getBagOrderString(drawOrder) != getBagOrderString(getDrawOrder(game.LetterSet)) {
+		addProblem("The letters left in the letter set do not match the recorded changes.")
+	}
+
+	// The draws of the turns must show up in the replay in the same order,
+	// along with draws of turns that have been taken back since
+	drawIdx := 0
+	for turnIdx, turn := range game.Turns {
+		for _, drawnLetter := range turn.DrawnLetters {
+			for drawIdx < len(drawnLetterIds) && drawnLetterIds[drawIdx] != drawnLetter.Id {
+				drawIdx++
+			}
+			if drawIdx == len(drawnLetterIds) {
+				addProblem("Letter %s drawn in turn %d has not been drawn from the letter set in that order.",
+					drawnLetter.Id, turnIdx+1)
+				return verification, nil
+			}
+			drawIdx++
+		}
+	}
+
+	verification.IsValid = len(verification.Problems) == 0
+	return verification, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+func mockCommittedGame(t *testing.T) *Game {
+	letterSet, err := GetFullLetterSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for letterIdx := range letterSet {
+		letterSet[letterIdx].Id = strconv.Itoa(letterIdx)
+	}
+
+	game := &Game{LetterSet: letterSet, Players: []Player{{}}}
+	game.commitToLetterSet()
+
+	drawnLetters := []Letter{}
+	for i := 0; i < 7; i++ {
+		letter, err := PopLetterFromSet(game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		drawnLetters = append(drawnLetters, letter)
+	}
+	game.Turns = []TurnRecord{{DrawnLetters: drawnLetters[3:]}}
+	game.returnLetterToSet(drawnLetters[0], 5)
+	game.takeLetterFromSet(2)
+	return game
+}
+
+func TestVerifyBag(t *testing.T) {
+
+	testCases := []struct {
+		description string
+		tamper      func(game *Game)
+		isValid     bool
+	}{
+		{"untouched game", func(game *Game) {}, true},
+		{"changed salt", func(game *Game) { game.BagSalt += "0" }, false},
+		{"reordered letter set", func(game *Game) {
+			game.LetterSet[0], game.LetterSet[1] = game.LetterSet[1], game.LetterSet[0]
+		}, false},
+		{"unrecorded draw", func(game *Game) {
+			game.LetterSet = game.LetterSet[:len(game.LetterSet)-1]
+		}, false},
+		{"unknown letter in turn", func(game *Game) {
+			game.Turns[0].DrawnLetters = append(game.Turns[0].DrawnLetters, Letter{Id: "unknown"})
+		}, false},
+	}
+
+	for _, testCase := range testCases {
+		game := mockCommittedGame(t)
+
+		_, err := game.VerifyBag()
+		if err == nil {
+			t.Errorf("%s: Expected the letter set of a running game not to be verified", testCase.description)
+		}
+		if proof := game.GetBagProof(); proof.Salt != "" || proof.InitialOrder != nil {
+			t.Errorf("%s: Expected the salt to be secret while the game is running", testCase.description)
+		}
+
+		game.GameOver = true
+		testCase.tamper(game)
+		verification, err := game.VerifyBag()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if verification.IsValid != testCase.isValid {
+			t.Errorf("%s: Expected the letter set to be valid: %t, Was: %t %v",
+				testCase.description, testCase.isValid, verification.IsValid, verification.Problems)
+		}
+		if testCase.isValid && (verification.NumberOfDraws != 8 || verification.NumberOfOutOfOrderDraws != 1) {
+			t.Errorf("%s: Expected 8 draws, one out of order, Was: %d and %d", testCase.description,
+				verification.NumberOfDraws, verification.NumberOfOutOfOrderDraws)
+		}
+	}
+}
+
+func TestSeededLetterSetIsReproducible(t *testing.T) {
+
+	for _, seed := range []int64{0, 42, -7} {
+		seededRandom, otherSeededRandom := NewSeededRandom(seed), NewSeededRandom(seed)
+		letterSet, err := GetFullLetterSetShuffledBy(seededRandom.Intn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		otherLetterSet, err := GetFullLetterSetShuffledBy(otherSeededRandom.Intn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lettersToString(letterSet) != lettersToString(otherLetterSet) {
+			t.Errorf("Expected the same letter set for seed %d, Was: %s and %s",
+				seed, lettersToString(letterSet), lettersToString(otherLetterSet))
+		}
+
+		// A source that is set up again, e.g. after loading
+		// the game, continues where the old one stopped
+		restoredRandom := &SeededRandom{Seed: seed, NumberOfValues: seededRandom.NumberOfValues}
+		for i := 0; i < 10; i++ {
+			if number, restoredNumber := seededRandom.Intn(100), restoredRandom.Intn(100); number != restoredNumber {
+				t.Errorf("Expected the restored source of seed %d to return %d, Was: %d", seed, number, restoredNumber)
+			}
+		}
+	}
+}
+
+func TestOnlyCasualGamesCanBeSeeded(t *testing.T) {
+
+	testCases := []struct {
+		options GameOptions
+		isValid bool
+	}{
+		{GameOptions{IsSeeded: true, Seed: 42, IsCasual: true}, true},
+		{GameOptions{IsSeeded: true, Seed: 42}, false},
+		{GameOptions{}, true},
+	}
+
+	for _, testCase := range testCases {
+		err := testCase.options.Validate()
+		if (err == nil) != testCase.isValid {
+			t.Errorf("Expected options %+v to be valid: %t, Was: %v", testCase.options, testCase.isValid, err)
+		}
+	}
+
+	_, err := CreateTournament(Account{}, "Seeded", TOURNAMENT_FORMAT_ROUND_ROBIN, 0,
+		GameOptions{IsSeeded: true, IsCasual: true})
+	if err == nil {
+		t.Error("Expected a tournament with seeded games to be rejected")
+	}
+}
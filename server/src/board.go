@@ -342,7 +342,7 @@ func GetVerticalWordAtTile(verticalTileIdx int, horizontalTileIdx int, tiles [][
 	var verticalWordTiles []Tile
 	for _, horizontalTiles := range tiles[outerTopTileOfWord:outerBottomTileOfWord] {
 		verticalWordTiles = append(verticalWordTiles, horizontalTiles[horizontalTileIdx])
-		log.Printf("Append letter %c", horizontalTiles[horizontalTileIdx].Letter.Character)
+		log.Printf("Append letter %c", horizontalTiles[horizontalTileIdx].Letter.GetPlayedCharacter())
 	}
 	return true, verticalWordTiles, outerTopTileOfWord
 
@@ -661,3 +661,84 @@ func HasUnlockedLetters(tiles [][]Tile) (bool, int, int) {
 	return false, -1, -1
 
 }
+
+type TileScore struct {
+	VerticalIdx   int
+	HorizontalIdx int
+	Character     rune
+
+	// Whether the letter has been placed in the current turn,
+	// only those tiles still have their effect
+	IsNew      bool
+	IsWildcard bool
+
+	// Point value of the letter and what it is worth on the tile
+	LetterPoints int
+	Points       int
+	Effect       SpecialTileEffect
+
+	// What the tile adds to the multiplier of the word
+	WordMultiplierBonus int
+}
+
+type WordScore struct {
+	Word  string
+	Tiles []TileScore
+
+	// Sum of the points of all tiles
+	LetterPoints int
+
+	// The word's points are the LetterPoints times this multiplier.
+	// Word effects add up, i.e. two double word tiles triple the word.
+	WordMultiplier int
+	Points         int
+}
+
+func (wordOnBoard *WordOnBoard) GetScore() WordScore {
+	// Calculate the points for the word, tile by tile
+	// with respect to the point value of each letter and the tile effects
+
+	wordScore := WordScore{WordMultiplier: 1}
+	isHorizontal := wordOnBoard.firstLetterYIdx == wordOnBoard.lastLetterYIdx
+
+	for tileIdx, tile := range wordOnBoard.wordTiles {
+		tileScore := TileScore{
+			VerticalIdx:   wordOnBoard.firstLetterYIdx,
+			HorizontalIdx: wordOnBoard.firstLetterXIdx,
+			Character:     tile.Letter.GetPlayedCharacter(),
+			IsNew:         !tile.IsLocked,
+			IsWildcard:    tile.Letter.IsWildcard(),
+			LetterPoints:  tile.Letter.Attributes.PointValue,
+			Effect:        tile.Effect,
+		}
+		if isHorizontal {
+			tileScore.HorizontalIdx += tileIdx
+		} else {
+			tileScore.VerticalIdx += tileIdx
+		}
+
+		// Wildcard letters are worth nothing, whatever they stand for
+		if tileScore.IsWildcard {
+			tileScore.LetterPoints = 0
+		}
+
+		tileScore.Points = tileScore.LetterPoints
+		if tile.Effect == DOUBLE_LETTER_TILE_EFFECT {
+			tileScore.Points *= 2
+		} else if tile.Effect == TRIPLE_LETTER_TILE_EFFECT {
+			tileScore.Points *= 3
+		} else if tile.Effect == DOUBLE_WORD_TILE_EFFECT {
+			tileScore.WordMultiplierBonus = 1
+		} else if tile.Effect == TRIPLE_WORD_TILE_EFFECT {
+			tileScore.WordMultiplierBonus = 2
+		}
+
+		wordScore.Word += string(tile.Letter.GetPlayedCharacter())
+		wordScore.LetterPoints += tileScore.Points
+		wordScore.WordMultiplier += tileScore.WordMultiplierBonus
+		wordScore.Tiles = append(wordScore.Tiles, tileScore)
+	}
+
+	wordScore.Points = wordScore.LetterPoints * wordScore.WordMultiplier
+	return wordScore
+}
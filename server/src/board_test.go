@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -21,7 +22,7 @@ var mockTiles = []Tile{
 
 func TestTileSliceToStringSuccess(t *testing.T) {
 
-	err := assertEquals("[{\"IsLocked\":true,\"Letter\":{\"Character\":123,\"Attributes\":{\"Occurrences\":2,\"PointValue\":4}},\"Effect\":0,\"PlacementIsLegal\":false},{\"IsLocked\":true,\"Letter\":{\"Character\":123,\"Attributes\":{\"Occurrences\":1,\"PointValue\":9}},\"Effect\":0,\"PlacementIsLegal\":true}]", TileSliceToString(mockTiles))
+	err := assertEquals("[{\"IsLocked\":true,\"Letter\":{\"Id\":\"\",\"Character\":123,\"Attributes\":{\"Occurrences\":2,\"PointValue\":4}},\"Effect\":0,\"PlacementIsLegal\":false},{\"IsLocked\":true,\"Letter\":{\"Id\":\"\",\"Character\":123,\"Attributes\":{\"Occurrences\":1,\"PointValue\":9}},\"Effect\":0,\"PlacementIsLegal\":true}]", TileSliceToString(mockTiles))
 	if err != nil {
 		t.Error(err.Error())
 	}
@@ -45,3 +46,36 @@ func TestTileSliceToStringNil(t *testing.T) {
 	}
 
 }
+
+func TestWildcardLetterScoresNothing(t *testing.T) {
+
+	wildcardLetter := Letter{Character: WILDCARD_CHARACTER, Attributes: letterDistribution[WILDCARD_CHARACTER]}
+	err := wildcardLetter.DesignateWildcard('q')
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wordOnBoard := WordOnBoard{
+		firstLetterYIdx: 7,
+		lastLetterYIdx:  7,
+		firstLetterXIdx: 7,
+		lastLetterXIdx:  8,
+		wordTiles: []Tile{
+			{Letter: wildcardLetter, Effect: TRIPLE_LETTER_TILE_EFFECT},
+			{Letter: Letter{Character: 'i', Attributes: letterDistribution['i']}, Effect: NO_TILE_EFFECT},
+		},
+	}
+
+	wordScore := wordOnBoard.GetScore()
+	if err := assertEquals("qi", wordScore.Word); err != nil {
+		t.Error(err)
+	}
+	if wordScore.Points != 1 || wordScore.Tiles[0].Points != 0 || !wordScore.Tiles[0].IsWildcard {
+		t.Errorf("Expected the wildcard letter to score 0 and the word 1 point, Was: %+v", wordScore)
+	}
+
+	tileJson := TileSliceToString(wordOnBoard.wordTiles[:1])
+	if !strings.Contains(tileJson, "\"Character\":42") || !strings.Contains(tileJson, "\"DesignatedCharacter\":113") {
+		t.Errorf("Expected the wildcard letter to be marked on the board, Was: %s", tileJson)
+	}
+}
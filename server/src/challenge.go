@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gole/golelibs"
+	"time"
+)
+
+// Rules for challenging the words of a move
+const (
+	// Invalid words are rejected right away, no challenges
+	CHALLENGE_RULE_NONE = "none"
+
+	// A failed challenge costs the challenger nothing
+	CHALLENGE_RULE_SINGLE = "single"
+
+	// A failed challenge costs the challenger their next turn
+	CHALLENGE_RULE_DOUBLE = "double"
+
+	// A failed challenge costs the challenger CHALLENGE_PENALTY_POINTS
+	CHALLENGE_RULE_FIVE_POINTS = "fivePoints"
+)
+
+var SUPPORTED_CHALLENGE_RULES = []string{
+	CHALLENGE_RULE_NONE,
+	CHALLENGE_RULE_SINGLE,
+	CHALLENGE_RULE_DOUBLE,
+	CHALLENGE_RULE_FIVE_POINTS,
+}
+
+// Time the opponents have to challenge a move before it is final
+var CHALLENGE_WINDOW_DURATION = 30 * time.Second
+
+var CHALLENGE_PENALTY_POINTS = 5
+
+type ProvisionalMove struct {
+	// Index of the move in the game's Turns
+	TurnIdx int
+
+	// End of the challenge window
+	Deadline time.Time
+
+	// Whether the move has used up the last letters and
+	// thus ends the game once it is final
+	EndsGame bool
+}
+
+type ChallengeRecord struct {
+	ChallengerIdx int
+
+	// Words of the move that are not valid,
+	// the challenge has failed if there are none
+	PhonyWords []string
+
+	// Points the challenger has lost for a failed challenge
+	PenaltyPoints int
+
+	// Whether the challenger loses their next turn for a failed challenge
+	LosesTurn bool
+}
+
+func (game *Game) HasChallengeRule() bool {
+	return game.Options.ChallengeRule != CHALLENGE_RULE_NONE && game.Options.ChallengeRule != ""
+}
+
+func (game *Game) makeMoveProvisional(endsGame bool) {
+	// Open the challenge window for the move that has just been recorded last
+
+	game.ProvisionalMove = &ProvisionalMove{
+		TurnIdx:  len(game.Turns) - 1,
+		Deadline: time.Now().UTC().Add(CHALLENGE_WINDOW_DURATION),
+		EndsGame: endsGame,
+	}
+	game.ScheduleChallengeDeadline()
+}
+
+func (game *Game) ScheduleChallengeDeadline() {
+	// Make sure the provisional move becomes final when the challenge
+	// window closes even if nobody asks for it
+
+	if game.ProvisionalMove == nil {
+		return
+	}
+
+	deadline := game.ProvisionalMove.Deadline
+	time.AfterFunc(time.Until(deadline), func() {
+		game.Acquire()
+		defer game.Release()
+
+		if game.ProvisionalMove != nil && game.ProvisionalMove.Deadline.Equal(deadline) {
+			game.CheckChallengeDeadline()
+		}
+	})
+}
+
+func (game *Game) CheckChallengeDeadline() {
+	// Make the provisional move final if its challenge window has closed
+
+	if game.ProvisionalMove == nil || time.Now().UTC().Before(game.ProvisionalMove.Deadline) {
+		return
+	}
+
+	game.acceptProvisionalMove()
+	game.PublishChange(GAME_EVENT_MOVE_ACCEPTED, nil)
+}
+
+func (game *Game) acceptProvisionalMove() {
+	// Make the provisional move final, ending the game if it has used up
+	// the last letters
+
+	if game.ProvisionalMove == nil {
+		return
+	}
+
+	endsGame := game.ProvisionalMove.EndsGame
+	game.ProvisionalMove = nil
+	if endsGame {
+		game.EndGame()
+	}
+}
+
+func (game *Game) ChallengeLastMove(challengerIdx int) (ChallengeRecord, error) {
+	// Challenge the words of the provisional move
+	// Requires:
+	// - The index of a player who has not played the move
+	// Guarantees:
+	// - If any of the words is not valid, the move is withdrawn and
+	//   the player who has played it loses the turn
+	// - Otherwise the move is final and the challenger is penalised
+	//   according to the game's challenge rule
+	// - Return the outcome of the challenge
+	// - Return an error if there is no move that can be challenged
+
+	game.CheckChallengeDeadline()
+
+	if game.ProvisionalMove == nil || game.ProvisionalMove.TurnIdx != len(game.Turns)-1 {
+		return ChallengeRecord{}, errors.New("There is no move that can be challenged.")
+	}
+
+	if challengerIdx < 0 || challengerIdx >= len(game.Players) {
+		return ChallengeRecord{}, errors.New(fmt.Sprintf("Player with index %d is not available.", challengerIdx))
+	}
+
+	challengedTurn := game.Turns[len(game.Turns)-1]
+	if challengedTurn.PlayerIdx == challengerIdx {
+		return ChallengeRecord{}, errors.New("Players cannot challenge their own move.")
+	}
+
+	challenge := ChallengeRecord{ChallengerIdx: challengerIdx}
+	for _, playedWord := range challengedTurn.Words {
+		if !golelibs.IsAValidWord(playedWord.Word) {
+			challenge.PhonyWords = append(challenge.PhonyWords, playedWord.Word)
+		}
+	}
+
+	if len(challenge.PhonyWords) > 0 {
+		game.ProvisionalMove = nil
+
+		revertedTurn, err := game.RevertLastTurn()
+		if err != nil {
+			return ChallengeRecord{}, err
+		}
+
+		game.Turns = append(game.Turns, TurnRecord{
+			PlayerIdx: revertedTurn.PlayerIdx,
+			Type:      TURN_TYPE_WITHDRAWN,
+			Time:      time.Now().UTC(),
+			Words:     revertedTurn.Words,
+			Challenge: &challenge,
+		})
+		game.GiveTurnToNextPlayer()
+
+		game.PublishChange(GAME_EVENT_MOVE_CHALLENGED, challenge)
+		return challenge, nil
+	}
+
+	switch game.Options.ChallengeRule {
+	case CHALLENGE_RULE_DOUBLE:
+		challenge.LosesTurn = true
+	case CHALLENGE_RULE_FIVE_POINTS:
+		challenge.PenaltyPoints = CHALLENGE_PENALTY_POINTS
+		game.Players[challengerIdx].Points -= CHALLENGE_PENALTY_POINTS
+	}
+	game.Turns[len(game.Turns)-1].Challenge = &challenge
+
+	game.acceptProvisionalMove()
+
+	if challenge.LosesTurn && !game.GameOver {
+		if challengerIdx == game.PlayerIdxWithTurn {
+			err := game.ReturnUnlockedLettersToHand()
+			if err != nil {
+				return ChallengeRecord{}, err
+			}
+			game.GiveTurnToNextPlayer()
+		} else {
+			game.Players[challengerIdx].LosesNextTurn = true
+		}
+	}
+
+	game.PublishChange(GAME_EVENT_MOVE_CHALLENGED, challenge)
+	return challenge, nil
+}
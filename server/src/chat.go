@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var MAX_CHAT_MESSAGE_LENGTH = 500
+var DEFAULT_CHAT_HISTORY_PAGE_SIZE = 50
+var MAX_CHAT_HISTORY_PAGE_SIZE = 200
+
+// A participant may send at most MAX_CHAT_MESSAGES_PER_INTERVAL
+// messages within CHAT_RATE_LIMIT_INTERVAL
+var MAX_CHAT_MESSAGES_PER_INTERVAL = 5
+var CHAT_RATE_LIMIT_INTERVAL = 10 * time.Second
+
+type ChatMessage struct {
+	// Sequence number of the message within the game, starting at 1
+	Seq           int
+	ParticipantId string
+	Name          string
+	Text          string
+	Time          time.Time
+
+	// Messages in a team's private channel can only be read
+	// by the members of the team on the player seat PlayerIdx
+	IsTeamMessage bool
+	PlayerIdx     int
+}
+
+func (chatMessage *ChatMessage) IsVisibleTo(participant *Participant) bool {
+	if !chatMessage.IsTeamMessage {
+		return true
+	}
+	return !participant.IsSpectator() && participant.PlayerIdx == chatMessage.PlayerIdx
+}
+
+type ChatHistoryPage struct {
+	// Messages in chronological order
+	Messages []ChatMessage
+
+	// Whether there are older messages than the ones on this page
+	HasMore bool
+}
+
+func (game *Game) PostChatMessage(participant *Participant, text string, toTeam bool) (ChatMessage, error) {
+	// Add a chat message to the game
+	// Requires:
+	// - An authenticated participant of the game
+	// - Whether the message is meant for the participant's team only,
+	//   which is only possible for team members in team games
+	// Guarantees:
+	// - The message is stored with the game and published over the
+	//   game's live update channel to the participants of the game only,
+	//   like the chat history. Team messages only go to the members of the team.
+	// - Return an error if the message is empty or too long,
+	//   if the participant has been muted or if the participant
+	//   has exceeded the rate limit
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ChatMessage{}, errors.New("Cannot post an empty chat message.")
+	}
+
+	if utf8.RuneCountInString(text) > MAX_CHAT_MESSAGE_LENGTH {
+		return ChatMessage{}, errors.New(fmt.Sprintf(
+			"Chat message is too long. At most %d characters are allowed.", MAX_CHAT_MESSAGE_LENGTH))
+	}
+
+	if participant.IsMuted {
+		return ChatMessage{}, errors.New("Cannot post chat message. Participant has been muted.")
+	}
+
+	if toTeam && (!game.IsTeamGame() || participant.IsSpectator()) {
+		return ChatMessage{}, errors.New("Only team members in team games can post to a team channel.")
+	}
+
+	now := time.Now().UTC()
+
+	var recentChatMessageTimes []time.Time
+	for _, sendTime := range participant.RecentChatMessageTimes {
+		if now.Sub(sendTime) < CHAT_RATE_LIMIT_INTERVAL {
+			recentChatMessageTimes = append(recentChatMessageTimes, sendTime)
+		}
+	}
+	if len(recentChatMessageTimes) >= MAX_CHAT_MESSAGES_PER_INTERVAL {
+		participant.RecentChatMessageTimes = recentChatMessageTimes
+		return ChatMessage{}, errors.New("Cannot post chat message. Too many messages, please slow down.")
+	}
+	participant.RecentChatMessageTimes = append(recentChatMessageTimes, now)
+
+	chatMessage := ChatMessage{
+		Seq:           len(game.ChatMessages) + 1,
+		ParticipantId: participant.Id,
+		Name:          participant.Name,
+		Text:          text,
+		Time:          now,
+		IsTeamMessage: toTeam,
+		PlayerIdx:     participant.PlayerIdx,
+	}
+
+	game.ChatMessages = append(game.ChatMessages, chatMessage)
+	if chatMessage.IsTeamMessage {
+		game.PublishPrivateChange(chatMessage.PlayerIdx, GAME_EVENT_CHAT_MESSAGE, chatMessage)
+	} else {
+		game.PublishParticipantChange(GAME_EVENT_CHAT_MESSAGE, chatMessage)
+	}
+
+	return chatMessage, nil
+}
+
+func (game *Game) GetChatHistory(reader *Participant, beforeSeq int, limit int) ChatHistoryPage {
+	// Return one page of the game's chat history
+	// Requires:
+	// - The participant that reads the history
+	// - The sequence number of the oldest message the client already has
+	//   or 0 to get the newest messages
+	// - The maximum amount of messages to return, 0 for the default
+	// Guarantees:
+	// - Return up to limit messages that are older than beforeSeq,
+	//   the newest of those first in line to be included
+	// - Messages of other teams' channels are left out
+
+	if limit <= 0 {
+		limit = DEFAULT_CHAT_HISTORY_PAGE_SIZE
+	}
+	if limit > MAX_CHAT_HISTORY_PAGE_SIZE {
+		limit = MAX_CHAT_HISTORY_PAGE_SIZE
+	}
+
+	// Messages are stored in order of their sequence number
+	// so the sequence number can be used to find the end of the page
+	endIdx := len(game.ChatMessages)
+	if beforeSeq > 0 && beforeSeq-1 < endIdx {
+		endIdx = beforeSeq - 1
+	}
+
+	var page ChatHistoryPage
+	for idx := endIdx - 1; idx >= 0; idx-- {
+		if !game.ChatMessages[idx].IsVisibleTo(reader) {
+			continue
+		}
+		if len(page.Messages) == limit {
+			page.HasMore = true
+			break
+		}
+		page.Messages = append(page.Messages, game.ChatMessages[idx])
+	}
+
+	// Collected newest first but returned in chronological order
+	for i, j := 0, len(page.Messages)-1; i < j; i, j = i+1, j-1 {
+		page.Messages[i], page.Messages[j] = page.Messages[j], page.Messages[i]
+	}
+
+	return page
+}
+
+func (game *Game) SetParticipantMuted(moderator *Participant, participantId string, isMuted bool) error {
+	// Mute or unmute a participant of the game
+	// Guarantees:
+	// - Only the creator of the game (see IsCreator) can mute other participants
+	// - Return an error if the moderator is not allowed to
+	//   mute or if the participant does not exist
+
+	if !game.IsCreator(moderator) {
+		return errors.New("Only the creator of the game can mute participants.")
+	}
+
+	participant, err := game.GetParticipantById(participantId)
+	if err != nil {
+		return err
+	}
+
+	if participant.Id == moderator.Id {
+		return errors.New("The creator of the game cannot mute themselves.")
+	}
+
+	participant.IsMuted = isMuted
+	game.PublishChange(GAME_EVENT_PARTICIPANT_MUTED, *participant)
+
+	return nil
+}
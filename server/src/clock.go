@@ -0,0 +1,285 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Modes of time control for a game
+const (
+	TIME_CONTROL_NONE = "none"
+
+	// Every player has a bank of time for the whole game and loses
+	// points for every started OVERTIME_PENALTY_INTERVAL beyond it
+	TIME_CONTROL_BANK = "bank"
+
+	// Every move has to be made within a fixed time,
+	// otherwise the player passes
+	TIME_CONTROL_PER_MOVE = "perMove"
+
+	// Every move has to be made within a number of days,
+	// otherwise the player is resigned
+	TIME_CONTROL_CORRESPONDENCE = "correspondence"
+)
+
+var DEFAULT_OVERTIME_PENALTY_POINTS = 10
+var OVERTIME_PENALTY_INTERVAL = time.Minute
+
+type TimeControl struct {
+	// One of the TIME_CONTROL modes, TIME_CONTROL_NONE if empty
+	Mode string
+
+	// Time bank per player for TIME_CONTROL_BANK
+	BankSeconds int
+
+	// Points lost per started OVERTIME_PENALTY_INTERVAL of overtime,
+	// DEFAULT_OVERTIME_PENALTY_POINTS if 0
+	OvertimePenaltyPoints int
+
+	// Overtime after which a player with TIME_CONTROL_BANK is resigned,
+	// never if 0, i.e. the player only loses points
+	MaxOvertimeSeconds int
+
+	// Time per move for TIME_CONTROL_PER_MOVE
+	MoveSeconds int
+
+	// Days per move for TIME_CONTROL_CORRESPONDENCE
+	DaysPerMove int
+}
+
+type PlayerClock struct {
+	// Time the player has used in all finished turns
+	UsedTime time.Duration `json:"-"`
+
+	// Time left for the player, only filled in by GetCurrentClock.
+	// Negative if a player is in overtime.
+	RemainingSeconds int
+
+	// When the current turn times out, only set for the player with the turn
+	Deadline time.Time
+
+	// Points the player loses for overtime when the game ends
+	OvertimePenalty int
+}
+
+func (timeControl *TimeControl) Validate() error {
+	// Check the time control of a new game and fill in the defaults
+
+	switch timeControl.Mode {
+	case "":
+		timeControl.Mode = TIME_CONTROL_NONE
+	case TIME_CONTROL_NONE:
+	case TIME_CONTROL_BANK:
+		if timeControl.BankSeconds <= 0 {
+			return errors.New("A time bank needs a positive number of seconds.")
+		}
+		if timeControl.OvertimePenaltyPoints == 0 {
+			timeControl.OvertimePenaltyPoints = DEFAULT_OVERTIME_PENALTY_POINTS
+		}
+		if timeControl.MaxOvertimeSeconds < 0 {
+			return errors.New("The overtime cannot be negative.")
+		}
+	case TIME_CONTROL_PER_MOVE:
+		if timeControl.MoveSeconds <= 0 {
+			return errors.New("A time limit per move needs a positive number of seconds.")
+		}
+	case TIME_CONTROL_CORRESPONDENCE:
+		if timeControl.DaysPerMove <= 0 {
+			return errors.New("A correspondence game needs a positive number of days per move.")
+		}
+	default:
+		return errors.New("Unknown time control " + timeControl.Mode)
+	}
+	return nil
+}
+
+func (game *Game) HasTimeControl() bool {
+	return game.Options.TimeControl.Mode != TIME_CONTROL_NONE && game.Options.TimeControl.Mode != ""
+}
+
+func (game *Game) hasTurnDeadline() bool {
+	// Whether turns time out, which they do under every time control
+	// except a time bank without a maximum overtime
+	timeControl := game.Options.TimeControl
+	return game.HasTimeControl() &&
+		!(timeControl.Mode == TIME_CONTROL_BANK && timeControl.MaxOvertimeSeconds == 0)
+}
+
+func (game *Game) getTimeLimitOfTurn(playerIdx int) time.Duration {
+	// Return how long the player may take for their turn
+	// from the start of the turn on, 0 if turns do not time out
+
+	if !game.hasTurnDeadline() {
+		return 0
+	}
+
+	timeControl := game.Options.TimeControl
+	switch timeControl.Mode {
+	case TIME_CONTROL_BANK:
+		bank := time.Duration(timeControl.BankSeconds) * time.Second
+		maxOvertime := time.Duration(timeControl.MaxOvertimeSeconds) * time.Second
+		return bank + maxOvertime - game.Players[playerIdx].Clock.UsedTime
+	case TIME_CONTROL_PER_MOVE:
+		return time.Duration(timeControl.MoveSeconds) * time.Second
+	case TIME_CONTROL_CORRESPONDENCE:
+		return time.Duration(timeControl.DaysPerMove) * 24 * time.Hour
+	}
+	return 0
+}
+
+func (game *Game) GetTurnDeadline() time.Time {
+	// Return when the turn of the player with the turn times out
+	return game.TurnStartedAt.Add(game.getTimeLimitOfTurn(game.PlayerIdxWithTurn))
+}
+
+func (game *Game) startTurnClock() {
+	// Start the clock of the player with the turn
+	game.TurnStartedAt = time.Now().UTC()
+	game.ScheduleClockDeadline()
+	game.ScheduleInactivityDeadline()
+}
+
+func (game *Game) chargeTurnTime() {
+	// Account the time since the turn has started to the player
+	// with the turn and restart the turn's clock from now on
+	game.chargeTurnTimeUntil(time.Now().UTC())
+}
+
+func (game *Game) chargeTurnTimeUntil(until time.Time) {
+	// Account the time from the start of the turn until the given time
+	// to the player with the turn and restart the turn's clock from now on.
+	// The time in between is not charged to anyone.
+
+	if game.HasTimeControl() && !game.TurnStartedAt.IsZero() && until.After(game.TurnStartedAt) {
+		game.Players[game.PlayerIdxWithTurn].Clock.UsedTime += until.Sub(game.TurnStartedAt)
+	}
+	game.TurnStartedAt = time.Now().UTC()
+}
+
+func (game *Game) ScheduleClockDeadline() {
+	// Make sure a turn times out at its deadline even if nobody
+	// tries to make a move after it
+
+	if !game.hasTurnDeadline() || game.GameOver {
+		return
+	}
+
+	turnStartedAt := game.TurnStartedAt
+	time.AfterFunc(time.Until(game.GetTurnDeadline()), func() {
+		game.Acquire()
+		defer game.Release()
+
+		if game.TurnStartedAt.Equal(turnStartedAt) {
+			game.CheckClock()
+		}
+	})
+}
+
+func (game *Game) CheckClock() error {
+	// Enforce the time control on the player with the turn
+	// Guarantees:
+	// - If the turn has timed out, the player passes in games with
+	//   TIME_CONTROL_PER_MOVE and is resigned in all others,
+	//   so that in games of more than two players the others play on
+	// - Return an error if the turn has timed out
+
+	if !game.hasTurnDeadline() || game.GameOver {
+		return nil
+	}
+
+	if time.Now().UTC().Before(game.GetTurnDeadline()) {
+		return nil
+	}
+
+	playerIdx := game.PlayerIdxWithTurn
+	if game.Options.TimeControl.Mode == TIME_CONTROL_PER_MOVE {
+		err := game.PassTurn()
+		if err != nil {
+			return err
+		}
+		game.PublishChange(GAME_EVENT_TURN_TIMED_OUT, TurnFinishedEventData{
+			PlayerIdxWithTurn: game.PlayerIdxWithTurn,
+			GameOver:          game.GameOver,
+		})
+	} else {
+		err := game.ResignPlayer(playerIdx, RESIGN_REASON_TIMED_OUT)
+		if err != nil {
+			return err
+		}
+	}
+
+	return errors.New(fmt.Sprintf("The time of %s is up.", game.Players[playerIdx].Name))
+}
+
+func (game *Game) getOvertimePenalty(usedTime time.Duration) int {
+	// Return the points lost for the given time used in a game
+	// with TIME_CONTROL_BANK
+	timeControl := game.Options.TimeControl
+	if timeControl.Mode != TIME_CONTROL_BANK {
+		return 0
+	}
+
+	overtime := usedTime - time.Duration(timeControl.BankSeconds)*time.Second
+	if overtime <= 0 {
+		return 0
+	}
+
+	startedIntervals := int((overtime + OVERTIME_PENALTY_INTERVAL - 1) / OVERTIME_PENALTY_INTERVAL)
+	return startedIntervals * timeControl.OvertimePenaltyPoints
+}
+
+func (game *Game) GetCurrentClock(playerIdx int) PlayerClock {
+	// Return the clock of the given player with the remaining time,
+	// deadline and penalty as of now so that it can be shown to the players
+	// Guarantees:
+	// - The game is left untouched, the time of a turn is only
+	//   charged once the turn ends
+
+	clock := game.Players[playerIdx].Clock
+	if !game.HasTimeControl() {
+		return clock
+	}
+
+	now := time.Now().UTC()
+	timeControl := game.Options.TimeControl
+	hasTurn := playerIdx == game.PlayerIdxWithTurn && !game.GameOver
+
+	usedTime := clock.UsedTime
+	clock.Deadline = time.Time{}
+	if hasTurn {
+		usedTime += now.Sub(game.TurnStartedAt)
+		if game.hasTurnDeadline() {
+			clock.Deadline = game.GetTurnDeadline()
+		}
+	}
+
+	if timeControl.Mode == TIME_CONTROL_BANK {
+		remainingTime := time.Duration(timeControl.BankSeconds)*time.Second - usedTime
+		clock.RemainingSeconds = int(remainingTime / time.Second)
+		if !game.GameOver {
+			clock.OvertimePenalty = game.getOvertimePenalty(usedTime)
+		}
+	} else if hasTurn {
+		clock.RemainingSeconds = int(clock.Deadline.Sub(now) / time.Second)
+	} else {
+		clock.RemainingSeconds = int(game.getTimeLimitOfTurn(playerIdx) / time.Second)
+	}
+	return clock
+}
+
+func (game *Game) applyOvertimePenalties() {
+	// Take the overtime penalties off the players' points
+	// once the game is over
+
+	if game.Options.TimeControl.Mode != TIME_CONTROL_BANK {
+		return
+	}
+
+	game.chargeTurnTime()
+	for playerIdx := range game.Players {
+		player := &game.Players[playerIdx]
+		player.Clock.OvertimePenalty = game.getOvertimePenalty(player.Clock.UsedTime)
+		player.Points -= player.Clock.OvertimePenalty
+	}
+}
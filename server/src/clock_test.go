@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetCurrentClockLeavesGameUntouched(t *testing.T) {
+
+	turnStartedAt := time.Now().UTC().Add(-85 * time.Second)
+	game := &Game{
+		Options: GameOptions{TimeControl: TimeControl{
+			Mode: TIME_CONTROL_BANK, BankSeconds: 60, OvertimePenaltyPoints: 10, MaxOvertimeSeconds: 600,
+		}},
+		Players:       []Player{{Clock: PlayerClock{UsedTime: 30 * time.Second}}, {}},
+		TurnStartedAt: turnStartedAt,
+	}
+
+	clock := game.GetCurrentClock(0)
+	if clock.RemainingSeconds > -54 || clock.RemainingSeconds < -56 {
+		t.Errorf("Expected about 55 seconds of overtime, Was: %d", clock.RemainingSeconds)
+	}
+	if clock.OvertimePenalty != 10 || clock.Deadline.IsZero() {
+		t.Errorf("Expected a penalty of 10 and a deadline, Was: %+v", clock)
+	}
+
+	otherClock := game.GetCurrentClock(1)
+	if otherClock.RemainingSeconds != 60 || !otherClock.Deadline.IsZero() {
+		t.Errorf("Expected the full bank and no deadline for the waiting player, Was: %+v", otherClock)
+	}
+
+	if game.Players[0].Clock != (PlayerClock{UsedTime: 30 * time.Second}) || !game.TurnStartedAt.Equal(turnStartedAt) {
+		t.Errorf("Expected the game not to be changed, Was: %+v started at %s",
+			game.Players[0].Clock, game.TurnStartedAt)
+	}
+}
+
+func TestTimedOutPlayerIsResigned(t *testing.T) {
+
+	useTemporaryStorage(t)
+
+	testCases := []struct {
+		description     string
+		timeControl     TimeControl
+		numberOfPlayers int
+		isResigned      bool
+		isGameOver      bool
+	}{
+		{"correspondence", TimeControl{Mode: TIME_CONTROL_CORRESPONDENCE, DaysPerMove: 1}, 3, true, false},
+		{"correspondence between two", TimeControl{Mode: TIME_CONTROL_CORRESPONDENCE, DaysPerMove: 1}, 2, true, true},
+		{"bank with maximum overtime", TimeControl{Mode: TIME_CONTROL_BANK, BankSeconds: 60, MaxOvertimeSeconds: 60}, 3, true, false},
+		{"bank without maximum overtime", TimeControl{Mode: TIME_CONTROL_BANK, BankSeconds: 60}, 3, false, false},
+	}
+
+	for _, testCase := range testCases {
+		game := &Game{
+			Options:       GameOptions{IsCasual: true, TimeControl: testCase.timeControl},
+			Tiles:         GetCleanTiles(),
+			LetterSet:     mockLettersFromString("xyz"),
+			TurnStartedAt: time.Now().UTC().Add(-25 * time.Hour),
+		}
+		for playerIdx := 0; playerIdx < testCase.numberOfPlayers; playerIdx++ {
+			game.Players = append(game.Players, Player{LettersInHand: mockLettersFromString("cat")})
+		}
+
+		err := game.CheckClock()
+		if (err != nil) != testCase.isResigned || game.Players[0].HasResigned != testCase.isResigned {
+			t.Errorf("%s: Expected the player to be resigned: %t, Was: %v", testCase.description, testCase.isResigned, err)
+		}
+		if game.GameOver != testCase.isGameOver {
+			t.Errorf("%s: Expected the game to be over: %t, Was: %t", testCase.description, testCase.isGameOver, game.GameOver)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Kinds of corrections the director of a tournament
+// can make to a running game of the tournament
+const (
+	CORRECTION_TYPE_SCORE       = "score"
+	CORRECTION_TYPE_UNDO_TURN   = "undoTurn"
+	CORRECTION_TYPE_RETURN_TILE = "returnTile"
+	CORRECTION_TYPE_FORFEIT     = "forfeit"
+)
+
+type DirectorCorrection struct {
+	Type              string
+	DirectorAccountId string
+	Time              time.Time
+
+	// Why the director has made the correction
+	Reason string
+
+	// Seat of the player the correction is about
+	PlayerIdx int
+
+	// Human readable description of what has been changed
+	Detail string
+}
+
+func (game *Game) CheckTileConservation() error {
+	// Check that no letter has been lost or duplicated
+	// Guarantees:
+	// - Return an error if the letters in the letter set, in the
+	//   players' hands, on the rack of a running duplicate round
+	//   and on the board do not add up to the
+	//   lettersAmount of a full letter set (see GetFullLetterSet)
+	//   or if a letter is found in more than one place
+
+	seenLetterIds := make(map[string]bool)
+	countLetter := func(letter Letter) error {
+		if seenLetterIds[letter.Id] {
+			return errors.New("Tile conservation violated. Letter " + letter.Id + " exists twice.")
+		}
+		seenLetterIds[letter.Id] = true
+		return nil
+	}
+
+	for _, letter := range game.LetterSet {
+		if err := countLetter(letter); err != nil {
+			return err
+		}
+	}
+
+	for _, player := range game.Players {
+		for _, letter := range player.LettersInHand {
+			if err := countLetter(letter); err != nil {
+				return err
+			}
+		}
+	}
+
+	if round, err := game.GetCurrentDuplicateRound(); err == nil && !round.IsFinished {
+		for _, letter := range round.Rack {
+			if err := countLetter(letter); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, tileRow := range game.Tiles {
+		for _, tile := range tileRow {
+			if tile.Letter == (Letter{}) {
+				continue
+			}
+			if err := countLetter(tile.Letter); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(seenLetterIds) != lettersAmount {
+		return errors.New(fmt.Sprintf(
+			"Tile conservation violated. Found %d letters, expected %d.",
+			len(seenLetterIds), lettersAmount))
+	}
+	return nil
+}
+
+func (game *Game) applyDirectorCorrection(director Account, correction DirectorCorrection,
+	correct func() (string, error)) (DirectorCorrection, error) {
+	// Make a correction to the game on behalf of the tournament director
+	// Requires:
+	// - A function that changes the game and returns a description
+	//   of what it has changed
+	// Guarantees:
+	// - Only the director of the tournament the game is part of
+	//   can correct a running game and must give a reason
+	// - The game is left unchanged if the correction fails
+	//   or if it would violate tile conservation
+	// - The correction is added to the game's audit trail and published
+
+	if game.GameOver {
+		return DirectorCorrection{}, errors.New("Cannot correct game. Game is over.")
+	}
+
+	if game.TournamentId == "" {
+		return DirectorCorrection{}, errors.New("Only tournament games can be corrected by a director.")
+	}
+
+	tournament, err := GetTournamentById(game.TournamentId)
+	if err != nil {
+		return DirectorCorrection{}, err
+	}
+
+	if tournament.DirectorAccountId != director.Id {
+		return DirectorCorrection{}, errors.New("Only the director of the tournament can correct its games.")
+	}
+
+	correction.Reason = strings.TrimSpace(correction.Reason)
+	if correction.Reason == "" {
+		return DirectorCorrection{}, errors.New("A reason is required for every correction.")
+	}
+
+	if correction.PlayerIdx < 0 || correction.PlayerIdx >= len(game.Players) {
+		return DirectorCorrection{}, errors.New(fmt.Sprintf(
+			"Player with index %d is not available.", correction.PlayerIdx))
+	}
+
+	gameBeforeCorrection, err := game.Clone()
+	if err != nil {
+		return DirectorCorrection{}, err
+	}
+
+	correction.Detail, err = correct()
+	if err == nil {
+		err = game.CheckTileConservation()
+	}
+	if err != nil {
+		*game = *gameBeforeCorrection
+		return DirectorCorrection{}, err
+	}
+
+	correction.DirectorAccountId = director.Id
+	correction.Time = time.Now().UTC()
+	game.Corrections = append(game.Corrections, correction)
+
+	log.Printf("Director %s corrected game %s: %s (%s)",
+		director.Username, game.Id, correction.Detail, correction.Reason)
+
+	game.PublishChange(GAME_EVENT_DIRECTOR_CORRECTION, correction)
+
+	return correction, nil
+}
+
+func (game *Game) CorrectScore(director Account, playerIdx int, points int, reason string) (DirectorCorrection, error) {
+	// Let the director set the points of a player,
+	// e.g. to fix a mis-entered score
+
+	correction := DirectorCorrection{Type: CORRECTION_TYPE_SCORE, PlayerIdx: playerIdx, Reason: reason}
+	return game.applyDirectorCorrection(director, correction, func() (string, error) {
+		player := &game.Players[playerIdx]
+		detail := fmt.Sprintf("Changed points of %s from %d to %d", player.Name, player.Points, points)
+		player.Points = points
+		return detail, nil
+	})
+}
+
+func (game *Game) UndoLastTurn(director Account, reason string) (DirectorCorrection, error) {
+	// Let the director take back the last finished turn,
+	// e.g. a move that was played by mistake.
+	// See RevertLastTurn.
+
+	if len(game.Turns) == 0 {
+		return DirectorCorrection{}, errors.New("Cannot undo turn. No turn has been played yet.")
+	}
+
+	lastTurn := game.Turns[len(game.Turns)-1]
+	correction := DirectorCorrection{Type: CORRECTION_TYPE_UNDO_TURN, PlayerIdx: lastTurn.PlayerIdx, Reason: reason}
+	return game.applyDirectorCorrection(director, correction, func() (string, error) {
+		revertedTurn, err := game.RevertLastTurn()
+		if err != nil {
+			return "", err
+		}
+
+		var words []string
+		for _, playedWord := range revertedTurn.Words {
+			words = append(words, playedWord.Word)
+		}
+		return fmt.Sprintf("Took back the turn of %s worth %d points (%s)",
+			game.Players[revertedTurn.PlayerIdx].Name, revertedTurn.Points,
+			strings.Join(words, ", ")), nil
+	})
+}
+
+func (game *Game) ReturnLetterToPlayer(director Account, playerIdx int, character rune, reason string) (DirectorCorrection, error) {
+	// Let the director hand a letter with the given character
+	// from the letter set back to a player, e.g. a letter that
+	// has wrongly been taken from the player's hand.
+	// Wildcard letters are asked for with the WILDCARD_CHARACTER.
+	// Guarantees:
+	// - Return an error if there is no such letter left in the
+	//   letter set or if the player's hand is full
+
+	correction := DirectorCorrection{Type: CORRECTION_TYPE_RETURN_TILE, PlayerIdx: playerIdx, Reason: reason}
+	return game.applyDirectorCorrection(director, correction, func() (string, error) {
+		for letterIdx := len(game.LetterSet) - 1; letterIdx >= 0; letterIdx-- {
+			letter := game.LetterSet[letterIdx]
+			if letter.Character != character {
+				continue
+			}
+
+			err := game.Players[playerIdx].AddLetterToHand(letter)
+			if err != nil {
+				return "", err
+			}
+			game.takeLetterFromSet(len(game.LetterSet) - 1 - letterIdx)
+
+			return fmt.Sprintf("Handed letter %c back to %s", character, game.Players[playerIdx].Name), nil
+		}
+		return "", errors.New(fmt.Sprintf("There is no letter %c left in the letter set.", character))
+	})
+}
+
+func (game *Game) AwardForfeit(director Account, playerIdx int, reason string) (DirectorCorrection, error) {
+	// Let the director end the game with a forfeit
+	// of the player on the given seat. See ForfeitPlayer.
+
+	// Ending the game changes ratings and the tournament, which cannot
+	// be rolled back, so the game is only ended once the correction
+	// has been accepted. The forfeit does not move any letters.
+	correction := DirectorCorrection{Type: CORRECTION_TYPE_FORFEIT, PlayerIdx: playerIdx, Reason: reason}
+	correction, err := game.applyDirectorCorrection(director, correction, func() (string, error) {
+		return fmt.Sprintf("Awarded a forfeit against %s", game.Players[playerIdx].Name), nil
+	})
+	if err != nil {
+		return DirectorCorrection{}, err
+	}
+
+	err = game.ForfeitPlayer(playerIdx)
+	if err != nil {
+		return DirectorCorrection{}, err
+	}
+	return correction, nil
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Time the players have to submit their move in each round of a duplicate game
+var DUPLICATE_ROUND_DURATION = 3 * time.Minute
+
+type DuplicateSubmission struct {
+	PlayerIdx  int
+	Time       time.Time
+	Placements []MovePlacement
+
+	// What the move is worth on the board as it was before the round
+	Words  []PlayedWord
+	Points int
+}
+
+type DuplicateRound struct {
+	Number int
+
+	// The letters all players play this round
+	Rack     []Letter
+	Deadline time.Time
+
+	// Submissions of the players, at most one per player.
+	// Only revealed once the round is finished.
+	Submissions []DuplicateSubmission
+	IsFinished  bool
+
+	// Index of the submission that has been played on the board,
+	// -1 if no valid move has been submitted
+	PlayedSubmissionIdx int
+}
+
+type DuplicateMoveSubmittedEventData struct {
+	RoundNumber int
+	PlayerIdx   int
+}
+
+func (game *Game) IsDuplicateGame() bool {
+	return game.Options.Variant == GAME_VARIANT_DUPLICATE
+}
+
+func (game *Game) GetCurrentDuplicateRound() (*DuplicateRound, error) {
+	if !game.IsDuplicateGame() || len(game.DuplicateRounds) == 0 {
+		return nil, errors.New("The game is not a duplicate game.")
+	}
+	return &game.DuplicateRounds[len(game.DuplicateRounds)-1], nil
+}
+
+func (game *Game) StartDuplicateRound(leftoverLetters []Letter) []Letter {
+	// Start the next round of a duplicate game
+	// Requires:
+	// - The letters of the previous round's rack that have not been played
+	// Guarantees:
+	// - The rack of the new round consists of the leftover letters,
+	//   filled up from the letter set as far as possible
+	// - The round ends when DUPLICATE_ROUND_DURATION has passed
+	//   or once every player has submitted a move
+	// - Return the letters that have been drawn from the letter set
+
+	rack := append([]Letter{}, leftoverLetters...)
+	var drawnLetters []Letter
+	for len(rack) < MAX_NUMBER_OF_LETTERS_IN_HAND {
+		letter, err := PopLetterFromSet(game)
+		if err != nil {
+			break
+		}
+		rack = append(rack, letter)
+		drawnLetters = append(drawnLetters, letter)
+	}
+
+	round := DuplicateRound{
+		Number:              len(game.DuplicateRounds) + 1,
+		Rack:                rack,
+		Deadline:            time.Now().UTC().Add(DUPLICATE_ROUND_DURATION),
+		PlayedSubmissionIdx: -1,
+	}
+	game.DuplicateRounds = append(game.DuplicateRounds, round)
+	game.ScheduleDuplicateRoundDeadline()
+
+	return drawnLetters
+}
+
+func (game *Game) ScheduleDuplicateRoundDeadline() {
+	// Make sure the current round is finished at its deadline even if
+	// nobody asks for it. The deadline is also checked whenever
+	// the round is accessed, e.g. after the server has been restarted.
+
+	round, err := game.GetCurrentDuplicateRound()
+	if err != nil || round.IsFinished {
+		return
+	}
+
+	roundNumber := round.Number
+	time.AfterFunc(time.Until(round.Deadline), func() {
+		game.Acquire()
+		defer game.Release()
+
+		round, err := game.GetCurrentDuplicateRound()
+		if err == nil && round.Number == roundNumber {
+			game.CheckDuplicateRoundDeadline()
+		}
+	})
+}
+
+func (game *Game) CheckDuplicateRoundDeadline() {
+	// Finish the current round of a duplicate game if its deadline has passed
+
+	round, err := game.GetCurrentDuplicateRound()
+	if err != nil || round.IsFinished || game.GameOver {
+		return
+	}
+
+	if time.Now().UTC().After(round.Deadline) {
+		game.finishDuplicateRound()
+	}
+}
+
+func (game *Game) SubmitDuplicateMove(playerIdx int, placements []MovePlacement) (DuplicateSubmission, error) {
+	// Submit a player's move for the current round of a duplicate game
+	// Requires:
+	// - The placements of letters from the round's rack
+	// Guarantees:
+	// - The move is scored against the board as it was before the round
+	// - A player can replace their submission until the deadline
+	// - Return the scored submission
+	// - Return an error if the deadline has passed or if the move is
+	//   not legal, in which case a previous submission is kept
+
+	game.CheckDuplicateRoundDeadline()
+
+	round, err := game.GetCurrentDuplicateRound()
+	if err != nil {
+		return DuplicateSubmission{}, err
+	}
+
+	if game.GameOver || round.IsFinished {
+		return DuplicateSubmission{}, errors.New("Cannot submit move. The round is over.")
+	}
+
+	if playerIdx < 0 || playerIdx >= len(game.Players) || game.Players[playerIdx].HasResigned {
+		return DuplicateSubmission{}, errors.New(fmt.Sprintf("Player with index %d is not available.", playerIdx))
+	}
+
+	var letterPlacements []LetterPlacement
+	usedLetterIds := make(map[string]bool)
+	for _, placement := range placements {
+		if usedLetterIds[placement.LetterId] {
+			return DuplicateSubmission{}, errors.New("Every letter of the rack can only be placed once.")
+		}
+		usedLetterIds[placement.LetterId] = true
+
+		letter, err := getLetterFromRack(round.Rack, placement.LetterId)
+		if err != nil {
+			return DuplicateSubmission{}, err
+		}
+		if letter.IsWildcard() {
+			err = letter.DesignateWildcard(placement.WildcardCharacter)
+			if err != nil {
+				return DuplicateSubmission{}, err
+			}
+		}
+
+		letterPlacements = append(letterPlacements, LetterPlacement{
+			VerticalIdx:   placement.VerticalIdx,
+			HorizontalIdx: placement.HorizontalIdx,
+			Letter:        letter,
+		})
+	}
+
+	evaluatedMove, err := game.EvaluatePlacements(letterPlacements)
+	if err != nil {
+		return DuplicateSubmission{}, err
+	}
+
+	submission := DuplicateSubmission{
+		PlayerIdx:  playerIdx,
+		Time:       time.Now().UTC(),
+		Placements: placements,
+		Words:      evaluatedMove.Words,
+		Points:     evaluatedMove.Points,
+	}
+
+	var submissions []DuplicateSubmission
+	for _, existingSubmission := range round.Submissions {
+		if existingSubmission.PlayerIdx != playerIdx {
+			submissions = append(submissions, existingSubmission)
+		}
+	}
+	round.Submissions = append(submissions, submission)
+
+	if len(round.Submissions) == game.GetNumberOfActivePlayers() {
+		game.finishDuplicateRound()
+	} else {
+		game.PublishChange(GAME_EVENT_DUPLICATE_MOVE_SUBMITTED, DuplicateMoveSubmittedEventData{
+			RoundNumber: round.Number,
+			PlayerIdx:   playerIdx,
+		})
+	}
+
+	return submission, nil
+}
+
+func (game *Game) withdrawDuplicateSubmission(playerIdx int) {
+	// Drop the submission of a player who has resigned from the current
+	// round and finish the round if everybody else has submitted
+
+	round, err := game.GetCurrentDuplicateRound()
+	if err != nil || round.IsFinished {
+		return
+	}
+
+	var submissions []DuplicateSubmission
+	for _, submission := range round.Submissions {
+		if submission.PlayerIdx != playerIdx {
+			submissions = append(submissions, submission)
+		}
+	}
+	round.Submissions = submissions
+
+	if len(round.Submissions) == game.GetNumberOfActivePlayers() {
+		game.finishDuplicateRound()
+	}
+}
+
+func getLetterFromRack(rack []Letter, letterId string) (Letter, error) {
+	for _, letter := range rack {
+		if letter.Id == letterId {
+			return letter, nil
+		}
+	}
+	return Letter{}, errors.New("There is no letter with ID " + letterId + " on the rack.")
+}
+
+func (game *Game) finishDuplicateRound() {
+	// Play the best submission of the current round on the board
+	// Guarantees:
+	// - Every player is awarded the points of their own submission
+	// - The submission with the most points is played on the board,
+	//   the earlier one if several are worth the same
+	// - The next round is started with the letters of the rack that
+	//   have not been played, unless no letters are left or nobody has
+	//   found a valid move, in which case the game is over
+
+	round, err := game.GetCurrentDuplicateRound()
+	if err != nil {
+		return
+	}
+	round.IsFinished = true
+
+	for submissionIdx, submission := range round.Submissions {
+		game.Players[submission.PlayerIdx].Points += submission.Points
+
+		if round.PlayedSubmissionIdx < 0 ||
+			submission.Points > round.Submissions[round.PlayedSubmissionIdx].Points ||
+			(submission.Points == round.Submissions[round.PlayedSubmissionIdx].Points &&
+				submission.Time.Before(round.Submissions[round.PlayedSubmissionIdx].Time)) {
+			round.PlayedSubmissionIdx = submissionIdx
+		}
+	}
+
+	if round.PlayedSubmissionIdx < 0 {
+		log.Printf("Nobody has found a move in round %d of game %s", round.Number, game.Id)
+		game.EndGame()
+		game.PublishChange(GAME_EVENT_DUPLICATE_ROUND_FINISHED, *round)
+		return
+	}
+
+	playedSubmission := round.Submissions[round.PlayedSubmissionIdx]
+	playedLetterIds := make(map[string]bool)
+	for _, placement := range playedSubmission.Placements {
+		letter, _ := getLetterFromRack(round.Rack, placement.LetterId)
+		if letter.IsWildcard() {
+			letter.DesignatedCharacter = placement.WildcardCharacter
+		}
+		// The board has not changed since the move has been evaluated
+		err = game.putLetterOnTile(placement.VerticalIdx, placement.HorizontalIdx, letter)
+		if err != nil {
+			log.Println("Could not play duplicate submission: ", err)
+		}
+		playedLetterIds[placement.LetterId] = true
+	}
+
+	var leftoverLetters []Letter
+	for _, letter := range round.Rack {
+		if !playedLetterIds[letter.Id] {
+			leftoverLetters = append(leftoverLetters, letter)
+		}
+	}
+
+	turnRecord := TurnRecord{
+		PlayerIdx:     playedSubmission.PlayerIdx,
+		Type:          TURN_TYPE_PLAY,
+		Time:          time.Now().UTC(),
+		Words:         playedSubmission.Words,
+		Points:        playedSubmission.Points,
+		PlacedLetters: game.GetUnlockedLetters(),
+		Notation:      game.GetNotationOfUnlockedLetters(),
+	}
+
+	game.LockLetters()
+	game.UpdatePlacementLegalityOfAllTiles()
+
+	// Starting the next round may move the rounds in memory
+	finishedRound := *round
+
+	if len(leftoverLetters) == 0 && len(game.LetterSet) == 0 {
+		game.Turns = append(game.Turns, turnRecord)
+		game.EndGame()
+	} else {
+		turnRecord.DrawnLetters = game.StartDuplicateRound(leftoverLetters)
+		game.Turns = append(game.Turns, turnRecord)
+	}
+
+	game.PublishChange(GAME_EVENT_DUPLICATE_ROUND_FINISHED, finishedRound)
+}
+
+func (game *Game) GetDuplicateRounds(viewer *Participant) ([]DuplicateRound, error) {
+	// Return all rounds of a duplicate game, oldest first
+	// Requires:
+	// - The participant asking or nil for clients that have not authenticated
+	// Guarantees:
+	// - The submissions of the running round are left out,
+	//   except for the viewer's own submission
+
+	game.CheckDuplicateRoundDeadline()
+
+	if !game.IsDuplicateGame() {
+		return nil, errors.New("The game is not a duplicate game.")
+	}
+
+	rounds := append([]DuplicateRound{}, game.DuplicateRounds...)
+	if len(rounds) == 0 {
+		return rounds, nil
+	}
+
+	currentRound := &rounds[len(rounds)-1]
+	if !currentRound.IsFinished {
+		var visibleSubmissions []DuplicateSubmission
+		for _, submission := range currentRound.Submissions {
+			if viewer != nil && !viewer.IsSpectator() && submission.PlayerIdx == viewer.PlayerIdx {
+				visibleSubmissions = append(visibleSubmissions, submission)
+			}
+		}
+		currentRound.Submissions = visibleSubmissions
+	}
+	return rounds, nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Types of the events that are published whenever the state of a game changes
+const (
+	GAME_EVENT_BOARD_CHANGED            = "boardChanged"
+	GAME_EVENT_HAND_CHANGED             = "handChanged"
+	GAME_EVENT_TURN_FINISHED            = "turnFinished"
+	GAME_EVENT_CHAT_MESSAGE             = "chatMessage"
+	GAME_EVENT_PARTICIPANT_JOINED       = "participantJoined"
+	GAME_EVENT_PARTICIPANT_MUTED        = "participantMuted"
+	GAME_EVENT_PLAYER_FORFEITED         = "playerForfeited"
+	GAME_EVENT_PLAYER_RESIGNED          = "playerResigned"
+	GAME_EVENT_DIRECTOR_CORRECTION      = "directorCorrection"
+	GAME_EVENT_DUPLICATE_MOVE_SUBMITTED = "duplicateMoveSubmitted"
+	GAME_EVENT_DUPLICATE_ROUND_FINISHED = "duplicateRoundFinished"
+	GAME_EVENT_MOVE_CHALLENGED          = "moveChallenged"
+	GAME_EVENT_MOVE_ACCEPTED            = "moveAccepted"
+	GAME_EVENT_TURN_TIMED_OUT           = "turnTimedOut"
+	GAME_EVENT_UNDO_REQUESTED           = "undoRequested"
+	GAME_EVENT_UNDO_DECLINED            = "undoDeclined"
+	GAME_EVENT_MOVE_UNDONE              = "moveUndone"
+	GAME_EVENT_EXPIRY_WARNING           = "expiryWarning"
+	GAME_EVENT_GAME_EXPIRED             = "gameExpired"
+	GAME_EVENT_STREAM_RESYNC            = "resync"
+	GAME_EVENT_STREAM_PRELUDE           = "hello"
+)
+
+// Amount of events that are kept per game so that a client
+// that has briefly been disconnected can catch up on what it missed
+var MAX_NUMBER_OF_RECENT_GAME_EVENTS = 100
+
+type GameEvent struct {
+	// The version of the game after the change this event describes
+	Version int
+	Type    string
+	Data    interface{}
+
+	// Private events, e.g. messages in a team's chat, are only
+	// delivered to the participants on the player seat with this index
+	IsPrivate bool `json:"-"`
+	PlayerIdx int  `json:"-"`
+
+	// Events that are only delivered to authenticated participants
+	// of the game, e.g. chat messages
+	IsForParticipants bool `json:"-"`
+}
+
+func (event *GameEvent) IsVisibleTo(participant *Participant) bool {
+	// Tell whether the event may be delivered to the given participant,
+	// nil for clients that have not authenticated
+	if event.IsForParticipants && participant == nil {
+		return false
+	}
+	if !event.IsPrivate {
+		return true
+	}
+	return participant != nil && !participant.IsSpectator() && participant.PlayerIdx == event.PlayerIdx
+}
+
+type GameChangeNotifier struct {
+	mutex sync.Mutex
+
+	// Latest version of the game this notifier has been told about
+	version int
+
+	// Channel that is closed (and then replaced) on every change
+	// so that every waiting request is woken up at once
+	// without the need of one goroutine per waiting request
+	changed chan struct{}
+
+	recentEvents []GameEvent
+}
+
+var gameChangeNotifiers = make(map[string]*GameChangeNotifier)
+var gameChangeNotifiersMutex sync.Mutex
+
+func GetGameChangeNotifier(game *Game) *GameChangeNotifier {
+	// Return the change notifier of the given game
+	// Guarantees:
+	// - Return the same notifier for every call with the same game Id
+	// - Create the notifier on first use, starting at the current
+	//   version of the game
+	// - Games that are read only never change, their notifier
+	//   is not kept so that it is not left behind
+
+	gameChangeNotifiersMutex.Lock()
+	defer gameChangeNotifiersMutex.Unlock()
+
+	notifier, exists := gameChangeNotifiers[game.Id]
+	if !exists {
+		notifier = &GameChangeNotifier{
+			version: game.Version,
+			changed: make(chan struct{}),
+		}
+		if !game.isReadOnly {
+			gameChangeNotifiers[game.Id] = notifier
+		}
+	}
+	return notifier
+}
+
+func (game *Game) PublishChange(eventType string, data interface{}) {
+	// Register a change of the game state and wake up everyone
+	// who is waiting for it.
+	// Guarantees:
+	// - The version of the game is increased by one
+	// - An event of the given type carrying the given data
+	//   is handed to all long-polling and streaming clients
+	// - The new state of the game is persisted so that it survives restarts
+
+	game.publishEvent(GameEvent{
+		Type: eventType,
+		Data: data,
+	})
+}
+
+func (game *Game) PublishPrivateChange(playerIdx int, eventType string, data interface{}) {
+	// Register a change that only the participants on the player seat
+	// with the given index may learn about. See PublishChange.
+	// Everybody else only sees the version of the game increase.
+
+	game.publishEvent(GameEvent{
+		Type:      eventType,
+		Data:      data,
+		IsPrivate: true,
+		PlayerIdx: playerIdx,
+	})
+}
+
+func (game *Game) PublishParticipantChange(eventType string, data interface{}) {
+	// Register a change that only the participants of the game
+	// may learn about. See PublishChange.
+	// Clients that have not authenticated only see the version of the game increase.
+
+	game.publishEvent(GameEvent{
+		Type:              eventType,
+		Data:              data,
+		IsForParticipants: true,
+	})
+}
+
+func (game *Game) publishEvent(event GameEvent) {
+	game.Version++
+	// Warning about inactivity must not count as activity itself
+	if event.Type != GAME_EVENT_EXPIRY_WARNING {
+		game.LastActivityAt = time.Now().UTC()
+	}
+	event.Version = game.Version
+	GetGameChangeNotifier(game).Publish(event)
+
+	// Only the changed game is written, see Save
+	err := game.Save()
+	if err != nil {
+		log.Println("Could not persist game: ", err)
+	}
+}
+
+func (notifier *GameChangeNotifier) Publish(event GameEvent) {
+	// Store the event and wake up all waiting requests
+
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+
+	notifier.version = event.Version
+	notifier.recentEvents = append(notifier.recentEvents, event)
+	if len(notifier.recentEvents) > MAX_NUMBER_OF_RECENT_GAME_EVENTS {
+		notifier.recentEvents = notifier.recentEvents[len(notifier.recentEvents)-MAX_NUMBER_OF_RECENT_GAME_EVENTS:]
+	}
+
+	close(notifier.changed)
+	notifier.changed = make(chan struct{})
+}
+
+func (notifier *GameChangeNotifier) GetVersion() int {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	return notifier.version
+}
+
+func (notifier *GameChangeNotifier) GetChangedChannel() (int, <-chan struct{}) {
+	// Return the current version along with the channel that will be
+	// closed as soon as the version moves past it.
+	// Both are read together so that no change can slip in between.
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	return notifier.version, notifier.changed
+}
+
+func (notifier *GameChangeNotifier) GetEventsSince(version int) ([]GameEvent, bool) {
+	// Return all stored events with a version higher than the given one
+	// Guarantees:
+	// - Return the events in the order in which they were published
+	// - Return false as second value if some of the events since the given
+	//   version have already been dropped from the buffer, in which case
+	//   the caller needs to resynchronise its complete state.
+
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+
+	if version >= notifier.version {
+		return nil, true
+	}
+
+	var events []GameEvent
+	for _, event := range notifier.recentEvents {
+		if event.Version > version {
+			events = append(events, event)
+		}
+	}
+
+	isComplete := len(events) > 0 && events[0].Version == version+1
+	return events, isComplete
+}
+
+func (notifier *GameChangeNotifier) WaitForVersionAfter(version int, timeout time.Duration, done <-chan struct{}) bool {
+	// Block until the version of the game moves past the given version,
+	// the timeout expires or the done channel is closed
+	// (e.g. because the client has disconnected).
+	// Guarantees:
+	// - Return true if the version has moved past the given version
+	// - Return false on timeout or if done has been closed
+	// - No goroutine is left behind in any of these cases
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		currentVersion, changed := notifier.GetChangedChannel()
+		if currentVersion > version {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return false
+		case <-done:
+			return false
+		}
+	}
+}
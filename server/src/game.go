@@ -6,12 +6,58 @@ import (
 	"gole/golelibs"
 	"log"
 	"strings"
+	"time"
 )
 
+// Variants of the game that change how players take turns
+const (
+	GAME_VARIANT_STANDARD = "standard"
+
+	// Two teams of two, each team sharing one hand and one score
+	GAME_VARIANT_TEAMS = "teams"
+
+	// All players play the same rack at the same time, see duplicate.go
+	GAME_VARIANT_DUPLICATE = "duplicate"
+)
+
+type GameOptions struct {
+	// One of the SUPPORTED_RULESETS, DEFAULT_RULESET if empty
+	Ruleset string
+
+	// One of the GAME_VARIANTs, GAME_VARIANT_STANDARD if empty
+	Variant string
+
+	// One of the SUPPORTED_CHALLENGE_RULES, CHALLENGE_RULE_NONE if empty
+	ChallengeRule string
+
+	// Casual games are not rated
+	IsCasual bool
+
+	// How much time the players have for their moves, see clock.go
+	TimeControl TimeControl
+
+	// Hours of inactivity after which the player with the turn is resigned,
+	// never if 0. Never shorter than the time control allows for a turn.
+	InactivityHours int
+
+	// Whether players may take back a move if all opponents agree.
+	// Only allowed in casual games.
+	AllowsUndo bool
+
+	// Seeded games shuffle their letter set with a random source of their
+	// own, so the same seed, ruleset and players always draw the same letters.
+	// The seed is kept secret until the game is over, see GetBagProof.
+	// Only casual games outside of tournaments can be seeded.
+	IsSeeded bool
+	Seed     int64 `json:"-"`
+}
+
 type Game struct {
 	Id      string
 	Players []Player
 
+	Options GameOptions
+
 	// hold index of player who has the current turn
 	PlayerIdxWithTurn int
 
@@ -23,10 +69,75 @@ type Game struct {
 	// i.e. that have not yet been handed to a player
 	LetterSet []Letter
 
+	// Hash of a secret salt and the initial order of the letter set,
+	// published when the game starts, see bag.go
+	BagCommitment string
+
+	// Revealed once the game is over
+	BagSalt          string            `json:"-"`
+	InitialLetterSet []Letter          `json:"-"`
+	LetterSetChanges []LetterSetChange `json:"-"`
+
+	// Source of randomness of seeded games, nil otherwise
+	Random *SeededRandom `json:"-"`
+
 	// Flag indicating whether the game is over
 	// will be fale until one player has no more letters in hand
 	// and the letter backlog is empty
 	GameOver bool
+
+	// Whether the result of the game has been accounted
+	// for in the players' ratings
+	IsRated bool
+
+	// Record of every finished turn, oldest first
+	Turns []TurnRecord
+
+	// Id of the tournament this game is part of, if any
+	TournamentId string
+
+	// Account that has created the game, which moderates its chat.
+	// The director in tournament games, empty if a guest has created the game.
+	CreatorAccountId string
+
+	// Increased by one on every change of the game state
+	// so that clients can tell whether they are up to date
+	Version int
+
+	// Players and spectators that have joined the game
+	// and can authenticate their requests
+	Participants []Participant
+
+	ChatMessages []ChatMessage
+
+	// Audit trail of all corrections made by a tournament director
+	Corrections []DirectorCorrection
+
+	// All rounds of a duplicate game, the current one last
+	DuplicateRounds []DuplicateRound
+
+	// The last move while it can still be challenged, nil otherwise
+	ProvisionalMove *ProvisionalMove
+
+	// When the player with the turn has got it
+	TurnStartedAt time.Time
+
+	// Open request to take back the last move, nil otherwise
+	UndoRequest *UndoRequest
+
+	CreatedAt time.Time
+
+	// Time of the last change of the game state
+	LastActivityAt time.Time
+
+	// When the participants have last been warned that
+	// the game is about to expire, see SweepGames
+	ExpiryWarningSentAt time.Time
+
+	// Set on copies read from the archive and on games that have been
+	// archived or have expired while a request was waiting for them.
+	// Changes to these games are never stored.
+	isReadOnly bool
 }
 
 var MIN_NUMBER_OF_PLAYERS = 2
@@ -46,7 +157,8 @@ func (game *Game) GetPlayerByName(playerName string) (Player, error) {
 
 func (game *Game) GetScoreBoard() map[string]int {
 	// Retrun a ScoreBoard Map with all player's names as Keys
-	// and their game points as associated value
+	// and their game points as associated value.
+	// In team games the keys are the names of the teams.
 
 	var scoreBoard = make(map[string]int)
 
@@ -58,22 +170,240 @@ func (game *Game) GetScoreBoard() map[string]int {
 	return scoreBoard
 }
 
-func AddPlayer(playerName string, game *Game) error {
+type ScoreBoardEntry struct {
+	Name   string
+	Points int
+
+	// Clock of the player in games with a time control
+	RemainingSeconds int
+	OvertimePenalty  int
+	HasTurn          bool
+}
+
+func (game *Game) GetDetailedScoreBoard() []ScoreBoardEntry {
+	// Return the scores of all players in the order of their seats
+	// together with the state of their clocks
+
+	var scoreBoard []ScoreBoardEntry
+	for playerIdx, player := range game.Players {
+		clock := game.GetCurrentClock(playerIdx)
+		scoreBoard = append(scoreBoard, ScoreBoardEntry{
+			Name:             player.Name,
+			Points:           player.Points,
+			RemainingSeconds: clock.RemainingSeconds,
+			OvertimePenalty:  clock.OvertimePenalty,
+			HasTurn:          playerIdx == game.PlayerIdxWithTurn && !game.GameOver,
+		})
+	}
+	return scoreBoard
+}
+
+type Seat struct {
+	// Id of the account that plays on this seat, empty for guests
+	AccountId string
+
+	// Throwaway name of a guest, ignored if an AccountId is given
+	GuestName string
+
+	// Name of the team the seat belongs to in team games
+	TeamName string
+}
+
+func (options *GameOptions) Validate() error {
+	// Check the options of a new game and fill in the defaults
+	if options.Variant == "" {
+		options.Variant = GAME_VARIANT_STANDARD
+	}
+	if options.Variant != GAME_VARIANT_STANDARD && options.Variant != GAME_VARIANT_TEAMS &&
+		options.Variant != GAME_VARIANT_DUPLICATE {
+		return errors.New("Unknown game variant " + options.Variant)
+	}
+
+	if options.ChallengeRule == "" {
+		options.ChallengeRule = CHALLENGE_RULE_NONE
+	}
+	isSupportedChallengeRule := false
+	for _, challengeRule := range SUPPORTED_CHALLENGE_RULES {
+		isSupportedChallengeRule = isSupportedChallengeRule || challengeRule == options.ChallengeRule
+	}
+	if !isSupportedChallengeRule {
+		return errors.New("Unknown challenge rule " + options.ChallengeRule)
+	}
+	if options.Variant == GAME_VARIANT_DUPLICATE && options.ChallengeRule != CHALLENGE_RULE_NONE {
+		return errors.New("Moves of duplicate games cannot be challenged.")
+	}
+
+	if options.AllowsUndo && (!options.IsCasual || options.Variant == GAME_VARIANT_DUPLICATE) {
+		return errors.New("Moves can only be taken back in casual games that are not duplicate games.")
+	}
+
+	// Whoever chooses the seed knows every draw in advance
+	if options.IsSeeded && !options.IsCasual {
+		return errors.New("Only casual games can be seeded.")
+	}
+
+	if options.InactivityHours < 0 {
+		return errors.New("The hours of inactivity cannot be negative.")
+	}
+
+	err := options.TimeControl.Validate()
+	if err != nil {
+		return err
+	}
+	if options.Variant == GAME_VARIANT_DUPLICATE && options.TimeControl.Mode != TIME_CONTROL_NONE {
+		return errors.New("Duplicate games are timed by their rounds and cannot have a time control.")
+	}
+
+	if options.Ruleset == "" {
+		options.Ruleset = DEFAULT_RULESET
+	}
+	for _, ruleset := range SUPPORTED_RULESETS {
+		if ruleset == options.Ruleset {
+			return nil
+		}
+	}
+	return errors.New("Unknown ruleset " + options.Ruleset)
+}
+
+func (game *Game) GetResultAgainst(playerIdx int, opponentIdx int) float64 {
+	// Return the result of a player against one opponent
+	// as it is used for rating: 1 for a win, 0.5 for a draw and 0 for a loss.
+	// The player with more points wins unless exactly one of the two
+	// has forfeited.
+
+	if game.Players[playerIdx].HasForfeited != game.Players[opponentIdx].HasForfeited {
+		if game.Players[playerIdx].HasForfeited {
+			return 0
+		}
+		return 1
+	}
+
+	playerPoints := game.Players[playerIdx].Points
+	opponentPoints := game.Players[opponentIdx].Points
+
+	if playerPoints > opponentPoints {
+		return 1
+	} else if playerPoints < opponentPoints {
+		return 0
+	}
+	return 0.5
+}
+
+func (game *Game) GetWinnerIdx() int {
+	// Return the index of the player who has won the game
+	// or -1 if the game is not over or if several players
+	// share the highest score
+
+	if !game.GameOver {
+		return -1
+	}
+
+	winnerIdx := -1
+	for playerIdx := range game.Players {
+		isWinner := true
+		for opponentIdx := range game.Players {
+			if opponentIdx != playerIdx && game.GetResultAgainst(playerIdx, opponentIdx) < 1 {
+				isWinner = false
+			}
+		}
+		if isWinner {
+			winnerIdx = playerIdx
+		}
+	}
+	return winnerIdx
+}
+
+func (game *Game) EndGame() {
+	// Mark the game as over and account for its result
+	// Guarantees:
+	// - GameOver is set
+	// - Overtime penalties are taken off the players' points
+	// - The players' ratings are updated unless the game is casual
+	// - The result is recorded in the game's tournament, if any
+
+	game.GameOver = true
+	game.applyOvertimePenalties()
+
+	err := UpdateRatingsForGame(game)
+	if err != nil {
+		log.Println("Could not update ratings: ", err)
+	}
+
+	if game.TournamentId != "" {
+		err = RecordTournamentGameResult(game)
+		if err != nil {
+			log.Println("Could not record tournament result: ", err)
+		}
+	}
+}
+
+func (game *Game) ForfeitPlayer(playerIdx int) error {
+	// Let the player on the given seat forfeit the game
+	// Guarantees:
+	// - The player loses against every other player
+	// - The game is over
+
+	if game.GameOver {
+		return errors.New("Cannot forfeit. Game is over.")
+	}
+
+	if playerIdx < 0 || playerIdx >= len(game.Players) {
+		return errors.New(fmt.Sprintf("Player with index %d is not available.", playerIdx))
+	}
+
+	game.Players[playerIdx].HasForfeited = true
+	game.EndGame()
+
+	game.PublishChange(GAME_EVENT_PLAYER_FORFEITED, PlayerForfeitedEventData{
+		PlayerIdx: playerIdx,
+		GameOver:  game.GameOver,
+	})
+
+	return nil
+}
+
+func AddPlayer(seat Seat, game *Game) error {
 	// Add a player to the list of players for the
 	// upcoming game play
+	// Guarantees:
+	// - Players on account seats are named after their account's username
+	//   and arrange their hand in the account's preferred mode
+	// - Return an error if the game is full, if the account does not exist
+	//   or has already been seated or if the name is taken already
 
 	if len(game.Players) >= MAX_NUMBER_OF_PLAYERS {
 		return errors.New("No more players can be added to the Game.")
 	}
 
-	_, err := game.GetPlayerByName(playerName)
+	player := Player{Name: strings.TrimSpace(seat.GuestName)}
+
+	if seat.AccountId != "" {
+		account, err := GetAccountById(seat.AccountId)
+		if err != nil {
+			return err
+		}
+		if _, isSeated := game.GetPlayerIdxOfAccount(account.Id); isSeated {
+			return errors.New("The account " + account.Username + " already has a seat in this game.")
+		}
+		player = Player{
+			Name:                  account.Username,
+			AccountId:             account.Id,
+			PreferredHandSortMode: account.PreferredHandSortMode,
+		}
+	}
+
+	if player.Name == "" {
+		return errors.New("A guest player needs a name.")
+	}
+
+	_, err := game.GetPlayerByName(player.Name)
 	if err == nil {
 		return errors.New("A player with this name already exists.")
 	}
 
-	player := Player{Name: playerName}
-
-	for i := 0; i < MAX_NUMBER_OF_LETTERS_IN_HAND; i++ {
+	// In duplicate games the players do not have hands of their own
+	// but all play the rack of the current round
+	for i := 0; i < MAX_NUMBER_OF_LETTERS_IN_HAND && !game.IsDuplicateGame(); i++ {
 		nextLetter, err := PopLetterFromSet(game)
 		if err != nil {
 			return err
@@ -105,6 +435,47 @@ func GetActivePlayer(game *Game) (*Player, error) {
 
 }
 
+func (game *Game) GiveTurnToNextPlayer() {
+	// Pass the turn on after the player with the turn has finished it
+	// Guarantees:
+	// - The players take turns in the order of their seats
+	// - In team games the turn passes to the other team and the next
+	//   time it is the finishing team's turn, the other teammate submits
+	// - Players who lose their next turn (see CHALLENGE_RULE_DOUBLE)
+	//   are skipped once and players who have resigned are always skipped
+	// - The time of the turn is accounted to the finishing player
+	//   and the clock of the next player is started
+
+	game.chargeTurnTime()
+
+	finishedPlayer := &game.Players[game.PlayerIdxWithTurn]
+	if len(finishedPlayer.Members) > 0 {
+		finishedPlayer.MemberIdxWithTurn = (finishedPlayer.MemberIdxWithTurn + 1) % len(finishedPlayer.Members)
+	}
+
+	game.PlayerIdxWithTurn = (game.PlayerIdxWithTurn + 1) % len(game.Players)
+	for skippedPlayers := 0; skippedPlayers < len(game.Players); skippedPlayers++ {
+		nextPlayer := &game.Players[game.PlayerIdxWithTurn]
+		if !nextPlayer.HasResigned && !nextPlayer.LosesNextTurn {
+			break
+		}
+		nextPlayer.LosesNextTurn = false
+		game.PlayerIdxWithTurn = (game.PlayerIdxWithTurn + 1) % len(game.Players)
+	}
+	log.Printf("Index of player with turn is now: %d", game.PlayerIdxWithTurn)
+
+	game.startTurnClock()
+}
+
+func (game *Game) ScheduleDeadlines() {
+	// Arm the timers of everything in the game that expires on its own,
+	// e.g. after the game has been loaded from storage
+	game.ScheduleDuplicateRoundDeadline()
+	game.ScheduleChallengeDeadline()
+	game.ScheduleClockDeadline()
+	game.ScheduleInactivityDeadline()
+}
+
 func PopLetterFromSet(game *Game) (Letter, error) {
 	// Pop the last letter (right end) from the
 	// letter string of the passed game structure instance.
@@ -114,14 +485,9 @@ func PopLetterFromSet(game *Game) (Letter, error) {
 	// - Returned letter will be removed from letter backlog string
 	// - Returns an empty letter and error
 	//   if no letter is left in letter backlog
+	// - The draw is recorded for the verification of the letter set
 
-	if len(game.LetterSet) < 1 {
-		return Letter{}, errors.New(
-			"Cannot pop letter from set. Empty.")
-	}
-	var letterToReturn = game.LetterSet[len(game.LetterSet)-1]
-	game.LetterSet = game.LetterSet[:len(game.LetterSet)-1]
-	return letterToReturn, nil
+	return game.takeLetterFromSet(0)
 }
 
 type PotentialPointsForWord struct {
@@ -130,6 +496,9 @@ type PotentialPointsForWord struct {
 	LastLetterXIdx  int
 	LastLetterYIdx  int
 	PotentialPoints int
+
+	// The word in standard notation, see notation.go
+	Notation string
 }
 
 type PotentialPointsForWords []*PotentialPointsForWord
@@ -172,6 +541,7 @@ func GetPotentialPoints(game *Game) (PotentialPointsForWords, error) {
 			FirstLetterXIdx: wordOnBoard.firstLetterXIdx,
 			FirstLetterYIdx: wordOnBoard.firstLetterYIdx,
 			PotentialPoints: pointsForWord,
+			Notation:        wordOnBoard.GetNotation(),
 		}
 
 		potentialPointsForWords = append(potentialPointsForWords, &potentialPointsForWord)
@@ -182,9 +552,13 @@ func GetPotentialPoints(game *Game) (PotentialPointsForWords, error) {
 }
 
 func PlaceLetter(game *Game, verticalTileIdx int,
-	horizontalTileIdx int, letterId string) error {
+	horizontalTileIdx int, letterId string, wildcardCharacter rune) error {
 	// Add a letter to the board.
 	//
+	// Requires:
+	// - For wildcard letters the character the letter is placed as,
+	//   0 to keep a designation chosen with ReplaceWildcard before.
+	//   Must be 0 for all other letters.
 	// Guarantees:
 	// - If successful, the affected letter will be moved away from the
 	//   active player's hand and put on the specified board tile
@@ -192,33 +566,80 @@ func PlaceLetter(game *Game, verticalTileIdx int,
 	//   -- placement is illeal
 	//   -- the game is over
 	//   -- the letter with the given ID is a wildcard letter, that
-	//      has not yet been replaced with an actual letter
+	//      has not been designated as an actual letter
 	//   -- the active player does not own the letter that is to be placed
 
-	isRawWildcardLetter, err := game.Players[game.PlayerIdxWithTurn].IsRawWildcardLetter(letterId)
+	err := game.placeLetterFromHand(verticalTileIdx, horizontalTileIdx, letterId, wildcardCharacter)
 	if err != nil {
 		return err
 	}
-	if isRawWildcardLetter {
-		return errors.New("Cannot place unsubstituted wildcard letter.")
-	}
+
+	game.PublishChange(GAME_EVENT_BOARD_CHANGED, nil)
+
+	return nil
+}
+
+func (game *Game) placeLetterFromHand(verticalTileIdx int,
+	horizontalTileIdx int, letterId string, wildcardCharacter rune) error {
+	// Move a letter from the hand of the player with the turn onto
+	// the board without publishing the change. See PlaceLetter.
 
 	if game.GameOver {
 		return errors.New("Cannot place letter. Game is over.")
 	}
 
-	if isLegal, reason := IsLegalPlacement(
-		verticalTileIdx, horizontalTileIdx, game.Tiles); !isLegal {
-		return errors.New("Cannot place letter. " + reason)
+	player := &game.Players[game.PlayerIdxWithTurn]
+	letterStruct, err := player.GetLetterFromHandById(letterId)
+	if err != nil {
+		return err
+	}
+	isFirstLetterOfMove := len(game.GetUnlockedLetters()) == 0
+	letterIdsInHand := player.getLetterIdsInHand()
+
+	if wildcardCharacter != 0 {
+		err = letterStruct.DesignateWildcard(wildcardCharacter)
+		if err != nil {
+			return err
+		}
 	}
 
-	var letterStruct Letter
-	letterStruct, err = game.Players[game.PlayerIdxWithTurn].PopLetterFromHand(letterId)
+	err = game.putLetterOnTile(verticalTileIdx, horizontalTileIdx, letterStruct)
 	if err != nil {
 		return err
 	}
 
-	game.Tiles[verticalTileIdx][horizontalTileIdx].Letter = letterStruct
+	_, err = player.PopLetterFromHand(letterId)
+	if err != nil {
+		return err
+	}
+	if isFirstLetterOfMove {
+		player.LetterIdsInHandBeforeMove = letterIdsInHand
+	}
+	game.markPlayerWithTurnActive()
+
+	return nil
+}
+
+func (game *Game) putLetterOnTile(verticalTileIdx int, horizontalTileIdx int, letter Letter) error {
+	// Put a letter on an empty board tile without taking it from a hand
+	// Guarantees:
+	// - Return an error if the placement is illegal or if the letter
+	//   is a wildcard letter that has not been designated
+
+	if letter.IsWildcard() && letter.DesignatedCharacter == 0 {
+		return errors.New("Cannot place unsubstituted wildcard letter.")
+	}
+
+	if !AreValidBoardCoordinates(verticalTileIdx, horizontalTileIdx) {
+		return errors.New("Cannot place letter. Index out of bounds")
+	}
+
+	if isLegal, reason := IsLegalPlacement(
+		verticalTileIdx, horizontalTileIdx, game.Tiles); !isLegal {
+		return errors.New("Cannot place letter. " + reason)
+	}
+
+	game.Tiles[verticalTileIdx][horizontalTileIdx].Letter = letter
 	game.UpdatePlacementLegalityOfAllTiles()
 
 	return nil
@@ -249,10 +670,13 @@ func RemoveLetter(game *Game, verticalTileIdx int, horizontalTileIdx int) error
 
 	// Overwrite letter on  tile with empty letter struct
 	game.Tiles[verticalTileIdx][horizontalTileIdx].Letter = Letter{}
+	game.markPlayerWithTurnActive()
 
 	// Update placement legality of whole board
 	game.UpdatePlacementLegalityOfAllTiles()
 
+	game.PublishChange(GAME_EVENT_BOARD_CHANGED, nil)
+
 	return nil
 
 }
@@ -277,38 +701,77 @@ func GetPointsForWord(wordOnBoard WordOnBoard, doCheckVailidity bool) (int, stri
 			"Can not get points for word. Too short.")
 	}
 
-	var word string
-	var wordPoints int
-	wordPointMultiplicator := 1
+	wordScore := wordOnBoard.GetScore()
+	word := wordScore.Word
 
-	for _, tile := range wordOnBoard.wordTiles {
+	log.Println("Word to check: " + word)
 
-		word += string(tile.Letter.Character)
+	if doCheckVailidity && !golelibs.IsAValidWord(word) {
+		return -1, word, errors.New("No t a valid word: " + word)
+	}
 
-		var letterPoints = tile.Letter.Attributes.PointValue
-		if tile.Effect == DOUBLE_LETTER_TILE_EFFECT {
-			letterPoints *= 2
-		} else if tile.Effect == TRIPLE_LETTER_TILE_EFFECT {
-			letterPoints *= 3
-		} else if tile.Effect == DOUBLE_WORD_TILE_EFFECT {
-			wordPointMultiplicator += 1
-		} else if tile.Effect == TRIPLE_WORD_TILE_EFFECT {
-			wordPointMultiplicator += 2
-		}
-		wordPoints += letterPoints
+	return wordScore.Points, word, nil
+
+}
 
+func (game *Game) ScoreNewWords(doCheckValidity bool) ([]PlayedWord, int, error) {
+	// Find and score all words formed by the unlocked letters on the board
+	// Guarantees:
+	// - Return every new word with its points and the sum of those points
+	// - Return a PlacementError if the letters do not form one legal move,
+	//   see CheckPlacementOfUnlockedLetters
+	// - Return an error if no new word has been found, if a letter is not
+	//   connected to the center tile or, if doCheckValidity is set,
+	//   if a word is not valid
+	// - The game is not changed
+
+	err := game.CheckPlacementOfUnlockedLetters()
+	if err != nil {
+		return nil, -1, err
 	}
 
-	log.Println("Word to check: " + word)
+	newWordsOnBoard, err := game.GetNewWordsFromBoard(true)
+	if err != nil {
+		return nil, -1, err
+	}
 
-	wordPoints *= wordPointMultiplicator
+	if len(newWordsOnBoard) == 0 {
+		return nil, -1, errors.New("No new words found on board.")
+	}
 
-	if doCheckVailidity && !golelibs.IsAValidWord(word) {
-		return -1, word, errors.New("No t a valid word: " + word)
+	var playedWords []PlayedWord
+	var points int
+	for _, wordOnBoard := range newWordsOnBoard {
+		pointsForWord, word, err := GetPointsForWord(wordOnBoard, doCheckValidity)
+		if err != nil {
+			return nil, -1, err
+		}
+		points += pointsForWord
+		playedWord := PlayedWord{
+			Word:   word,
+			Points: pointsForWord,
+		}
+		for tileIdx, tile := range wordOnBoard.wordTiles {
+			if tile.Letter.IsWildcard() {
+				playedWord.WildcardIdxs = append(playedWord.WildcardIdxs, tileIdx)
+			}
+		}
+		playedWords = append(playedWords, playedWord)
 	}
 
-	return wordPoints, word, nil
+	return playedWords, points, nil
+}
+
+type PlayerForfeitedEventData struct {
+	PlayerIdx int
+	GameOver  bool
+}
 
+type TurnFinishedEventData struct {
+	GainedPoints      int
+	Words             []string
+	PlayerIdxWithTurn int
+	GameOver          bool
 }
 
 func FinishTurn(game *Game) (int, []string, error) {
@@ -324,37 +787,54 @@ func FinishTurn(game *Game) (int, []string, error) {
 	//   and nil for error
 	// - If turn was unsuccessful, return -1, nil and the error
 	//   This inclused the case that no new words were found on the board.
+	// - In games with a challenge rule the words are not checked but the
+	//   move is only provisional until the challenge window has closed
+	//   or the next player finishes their turn
+	// - In games with a time control a move made after the turn
+	//   has timed out is rejected
+	// - A provisional move of the previous player only becomes final
+	//   if the turn is finished. A turn cannot be finished while the
+	//   previous move that ends the game is provisional.
 
 	// Stores the words that have been successfully confirmed
 	// in this round
 	var confirmedWords []string
 
-	// Stores the points that have been gaines in this round.
-	// After all words have been confirmed,
-	// these points will be accounted to the player.
-	var points int
+	err := game.CheckClock()
+	if err != nil {
+		return -1, nil, err
+	}
 
-	newWordsOnBoard, err := game.GetNewWordsFromBoard(true)
+	if game.ProvisionalMove != nil && game.ProvisionalMove.EndsGame {
+		return -1, nil, errors.New("Cannot finish turn. The game is over once the last move is final.")
+	}
 
+	playedWords, points, err := game.ScoreNewWords(!game.HasChallengeRule())
 	if err != nil {
 		return -1, nil, err
 	}
 
-	if len(newWordsOnBoard) == 0 {
-		return -1, nil, errors.New("No new words found on board.")
+	// Nothing can reject the turn anymore
+	game.acceptProvisionalMove()
+
+	turnRecord := TurnRecord{
+		PlayerIdx:     game.PlayerIdxWithTurn,
+		Type:          TURN_TYPE_PLAY,
+		Time:          time.Now().UTC(),
+		PlacedLetters: game.GetUnlockedLetters(),
+		Words:         playedWords,
+		Notation:      game.GetNotationOfUnlockedLetters(),
+
+		LetterIdsInHandBeforeTurn: game.Players[game.PlayerIdxWithTurn].LetterIdsInHandBeforeMove,
 	}
 
-	for _, wordOnBoard := range newWordsOnBoard {
-		pointsForWord, newConfirmdWord, err := GetPointsForWord(wordOnBoard, true)
-		if err != nil {
-			return -1, nil, err
-		}
-		points += pointsForWord
-		confirmedWords = append(confirmedWords, newConfirmdWord)
+	for _, playedWord := range playedWords {
+		confirmedWords = append(confirmedWords, playedWord.Word)
 	}
 
 	// Add earned points to current player
 	game.Players[game.PlayerIdxWithTurn].Points += points
+	turnRecord.Points = points
 
 	// Fill up player hand with new letters
 	numberOflettersToAdd := MAX_NUMBER_OF_LETTERS_IN_HAND - len(game.Players[game.PlayerIdxWithTurn].LettersInHand)
@@ -368,20 +848,33 @@ func FinishTurn(game *Game) (int, []string, error) {
 		if err != nil {
 			return -1, nil, err
 		}
+		turnRecord.DrawnLetters = append(turnRecord.DrawnLetters, newLetter)
 	}
+	game.Players[game.PlayerIdxWithTurn].applyPreferredHandSortMode()
+
+	game.Turns = append(game.Turns, turnRecord)
 
 	// If the player hand is empty at this stage.
 	// The game is considered over as at least one player has no letters left
 	// anymore.
-	if len(game.Players[game.PlayerIdxWithTurn].LettersInHand) < 1 {
-		game.GameOver = true
+	isHandEmpty := len(game.Players[game.PlayerIdxWithTurn].LettersInHand) < 1
+	if game.HasChallengeRule() {
+		game.makeMoveProvisional(isHandEmpty)
+	} else if isHandEmpty {
+		game.EndGame()
 	}
 
 	game.LockLetters()
 	game.UpdatePlacementLegalityOfAllTiles()
 
-	// Give turn to next player
-	game.PlayerIdxWithTurn = (game.PlayerIdxWithTurn + 1) % len(game.Players)
-	log.Printf("Index of player with turn is now: %d", game.PlayerIdxWithTurn)
+	game.GiveTurnToNextPlayer()
+
+	game.PublishChange(GAME_EVENT_TURN_FINISHED, TurnFinishedEventData{
+		GainedPoints:      points,
+		Words:             confirmedWords,
+		PlayerIdxWithTurn: game.PlayerIdxWithTurn,
+		GameOver:          game.GameOver,
+	})
+
 	return points, confirmedWords, nil
 }
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stages a game goes through
+const (
+	// Not every seat has been joined and no move has been played yet
+	GAME_STATUS_LOBBY    = "lobby"
+	GAME_STATUS_ACTIVE   = "active"
+	GAME_STATUS_FINISHED = "finished"
+)
+
+var DEFAULT_GAME_LIST_PAGE_SIZE = 20
+var MAX_GAME_LIST_PAGE_SIZE = 100
+
+type GamePlayerIdentity struct {
+	Name string
+
+	// Empty for guests
+	AccountId string
+}
+
+type GameSummary struct {
+	Id             string
+	Status         string
+	Ruleset        string
+	Variant        string
+	CreatedAt      time.Time
+	LastActivityAt time.Time
+	Players        []GamePlayerIdentity
+	TournamentId   string
+}
+
+type GameListFilter struct {
+	// Name or account Id of a player of the game, ignored if empty
+	Player string
+
+	// One of the GAME_STATUSes, ignored if empty
+	Status string
+
+	// Only games created within this range, ignored if zero
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+
+	// Only finished games in which this word has been played, ignored if empty
+	PlayedWord string
+
+	// Account that asks for the games. Games that are not finished yet
+	// are only listed for the accounts seated in them, since the Id of a
+	// guest game is all it takes to play in it. Guests therefore never
+	// get their running games listed and have to keep their Ids
+	// themselves. Only finished games are listed if empty.
+	ViewerAccountId string
+}
+
+type GameListPage struct {
+	Games []GameSummary
+
+	// Number of games that match the filter across all pages
+	Total int
+}
+
+func (game *Game) hasEmptySeats() bool {
+	// Whether any player, or in team games any team member,
+	// has not joined the game yet
+
+	for playerIdx, player := range game.Players {
+		numberOfSeats := 1
+		if len(player.Members) > 0 {
+			numberOfSeats = len(player.Members)
+		}
+		for memberIdx := 0; memberIdx < numberOfSeats; memberIdx++ {
+			isTaken := false
+			for _, participant := range game.Participants {
+				isTaken = isTaken || (participant.PlayerIdx == playerIdx && participant.MemberIdx == memberIdx)
+			}
+			if !isTaken {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (game *Game) GetStatus() string {
+	// Return the GAME_STATUS the game is in
+
+	if game.GameOver {
+		return GAME_STATUS_FINISHED
+	}
+	if len(game.Turns) == 0 && game.hasEmptySeats() {
+		return GAME_STATUS_LOBBY
+	}
+	return GAME_STATUS_ACTIVE
+}
+
+func (game *Game) GetSummary() GameSummary {
+	// Return what is needed to find and list the game
+
+	summary := GameSummary{
+		Id:             game.Id,
+		Status:         game.GetStatus(),
+		Ruleset:        game.Options.Ruleset,
+		Variant:        game.Options.Variant,
+		CreatedAt:      game.CreatedAt,
+		LastActivityAt: game.LastActivityAt,
+		TournamentId:   game.TournamentId,
+	}
+	for _, player := range game.Players {
+		if len(player.Members) == 0 {
+			summary.Players = append(summary.Players, GamePlayerIdentity{Name: player.Name, AccountId: player.AccountId})
+		}
+		for _, member := range player.Members {
+			summary.Players = append(summary.Players, GamePlayerIdentity{Name: member.Name, AccountId: member.AccountId})
+		}
+	}
+	return summary
+}
+
+func (summary *GameSummary) hasAccount(accountId string) bool {
+	// Whether the account with the given Id is seated in the game
+	for _, player := range summary.Players {
+		if player.AccountId != "" && player.AccountId == accountId {
+			return true
+		}
+	}
+	return false
+}
+
+func (game *Game) hasPlayedWord(word string) bool {
+	for _, turn := range game.Turns {
+		if turn.Type != TURN_TYPE_PLAY {
+			continue
+		}
+		for _, playedWord := range turn.Words {
+			if strings.EqualFold(playedWord.Word, word) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (filter *GameListFilter) Matches(summary GameSummary, hasPlayedWord func(word string) bool) bool {
+	// Whether the game passes all conditions of the filter
+	// Requires:
+	// - The summary of the game and a function that tells
+	//   whether a word has been played in the game
+
+	if filter.Status != "" && summary.Status != filter.Status {
+		return false
+	}
+
+	if summary.Status != GAME_STATUS_FINISHED && !summary.hasAccount(filter.ViewerAccountId) {
+		return false
+	}
+
+	if !filter.CreatedAfter.IsZero() && summary.CreatedAt.Before(filter.CreatedAfter) {
+		return false
+	}
+	if !filter.CreatedBefore.IsZero() && summary.CreatedAt.After(filter.CreatedBefore) {
+		return false
+	}
+
+	if filter.Player != "" {
+		hasPlayer := false
+		for _, player := range summary.Players {
+			hasPlayer = hasPlayer || player.AccountId == filter.Player ||
+				strings.EqualFold(player.Name, strings.TrimSpace(filter.Player))
+		}
+		if !hasPlayer {
+			return false
+		}
+	}
+
+	if filter.PlayedWord != "" {
+		if summary.Status != GAME_STATUS_FINISHED || !hasPlayedWord(strings.TrimSpace(filter.PlayedWord)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func ListGames(filter GameListFilter, offset int, limit int) (GameListPage, error) {
+	// Return one page of the games that match the filter
+	// Requires:
+	// - The number of matching games to skip and the maximum
+	//   amount of games to return, 0 for the default
+	// Guarantees:
+	// - The most recently active games come first
+	// - Archived games are included
+	// - Return an error for an unknown status
+
+	if filter.Status != "" && filter.Status != GAME_STATUS_LOBBY &&
+		filter.Status != GAME_STATUS_ACTIVE && filter.Status != GAME_STATUS_FINISHED {
+		return GameListPage{}, errors.New("Unknown game status " + filter.Status)
+	}
+
+	if limit <= 0 {
+		limit = DEFAULT_GAME_LIST_PAGE_SIZE
+	}
+	if limit > MAX_GAME_LIST_PAGE_SIZE {
+		limit = MAX_GAME_LIST_PAGE_SIZE
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var matchingGames []GameSummary
+	for _, game := range getGamesInMemory() {
+		game.Acquire()
+		summary := game.GetSummary()
+		if filter.Matches(summary, game.hasPlayedWord) {
+			matchingGames = append(matchingGames, summary)
+		}
+		game.Release()
+	}
+	// Only finished games are archived
+	if filter.Status == "" || filter.Status == GAME_STATUS_FINISHED {
+		for _, entry := range getArchivedGameIndexEntries() {
+			if filter.Matches(entry.summary, entry.hasPlayedWord) {
+				matchingGames = append(matchingGames, entry.summary)
+			}
+		}
+	}
+
+	sort.SliceStable(matchingGames, func(i, j int) bool {
+		return matchingGames[i].LastActivityAt.After(matchingGames[j].LastActivityAt)
+	})
+
+	page := GameListPage{Games: []GameSummary{}, Total: len(matchingGames)}
+	if offset < len(matchingGames) {
+		endIdx := offset + limit
+		if endIdx > len(matchingGames) {
+			endIdx = len(matchingGames)
+		}
+		page.Games = matchingGames[offset:endIdx]
+	}
+	return page, nil
+}
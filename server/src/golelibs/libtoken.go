@@ -0,0 +1,21 @@
+package golelibs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+)
+
+func GetNewSecretToken() string {
+	// Generate a new random token that can be handed out
+	// to clients to authenticate later requests.
+	// Guarantees:
+	// - Return a hex encoded string of 32 random bytes
+	//   read from the operating system's secure random source
+	tokenBytes := make([]byte, 32)
+	_, err := rand.Read(tokenBytes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return hex.EncodeToString(tokenBytes)
+}
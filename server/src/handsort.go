@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Named ways in which the server can arrange the letters in a hand
+const (
+	HAND_SORT_MODE_ALPHABETICAL    = "alphabetical"
+	HAND_SORT_MODE_POINTS          = "points"
+	HAND_SORT_MODE_VOWELS_FIRST    = "vowelsFirst"
+	HAND_SORT_MODE_WILDCARDS_FIRST = "wildcardsFirst"
+
+	// Letters that form common prefixes and suffixes are put next to
+	// each other, prefixes at the front and suffixes at the back
+	HAND_SORT_MODE_FRAGMENTS = "fragments"
+)
+
+var HAND_SORT_MODES = []string{
+	HAND_SORT_MODE_ALPHABETICAL,
+	HAND_SORT_MODE_POINTS,
+	HAND_SORT_MODE_VOWELS_FIRST,
+	HAND_SORT_MODE_WILDCARDS_FIRST,
+	HAND_SORT_MODE_FRAGMENTS,
+}
+
+// Fragments looked for by HAND_SORT_MODE_FRAGMENTS, in order of preference.
+// At most one prefix and one suffix are put together.
+var WORD_FRAGMENT_SUFFIXES = []string{"tion", "ness", "ing", "est", "ers", "ies", "ed", "er", "es", "ly"}
+var WORD_FRAGMENT_PREFIXES = []string{"dis", "pre", "un", "re", "de"}
+
+func IsHandSortMode(mode string) bool {
+	for _, handSortMode := range HAND_SORT_MODES {
+		if mode == handSortMode {
+			return true
+		}
+	}
+	return false
+}
+
+func getLetterSortRank(letter Letter, mode string) int {
+	// Letters with a lower rank come first, letters of the same
+	// rank are sorted alphabetically. Wildcard letters come last
+	// unless they are explicitly put first.
+
+	if letter.IsWildcard() {
+		if mode == HAND_SORT_MODE_WILDCARDS_FIRST {
+			return 0
+		}
+		return 2
+	}
+	if mode == HAND_SORT_MODE_VOWELS_FIRST && !IsVowel(letter.Character) {
+		return 1
+	}
+	if mode == HAND_SORT_MODE_WILDCARDS_FIRST {
+		return 1
+	}
+	return 0
+}
+
+func sortLettersByMode(letters []Letter, mode string) {
+	// Sort the letters in place, see getLetterSortRank.
+	// Ties are broken by the Id so that the order is always the same.
+
+	sort.SliceStable(letters, func(i, j int) bool {
+		rankI, rankJ := getLetterSortRank(letters[i], mode), getLetterSortRank(letters[j], mode)
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		if mode == HAND_SORT_MODE_POINTS && letters[i].Attributes.PointValue != letters[j].Attributes.PointValue {
+			return letters[i].Attributes.PointValue > letters[j].Attributes.PointValue
+		}
+		if letters[i].Character != letters[j].Character {
+			return letters[i].Character < letters[j].Character
+		}
+		return letters[i].Id < letters[j].Id
+	})
+}
+
+func takeWordFragment(letters []Letter, fragments []string) ([]Letter, []Letter) {
+	// Take the letters of the first fragment that can be spelled
+	// with the given letters. Wildcard letters are not used.
+	// Return the letters of the fragment in order and the remaining letters.
+
+	for _, fragment := range fragments {
+		var usedLetterIdxs []int
+		for _, character := range fragment {
+			for letterIdx, letter := range letters {
+				if letter.Character == character && !containsInt(usedLetterIdxs, letterIdx) {
+					usedLetterIdxs = append(usedLetterIdxs, letterIdx)
+					break
+				}
+			}
+		}
+		if len(usedLetterIdxs) != len(fragment) {
+			continue
+		}
+
+		var fragmentLetters, remainingLetters []Letter
+		for _, letterIdx := range usedLetterIdxs {
+			fragmentLetters = append(fragmentLetters, letters[letterIdx])
+		}
+		for letterIdx, letter := range letters {
+			if !containsInt(usedLetterIdxs, letterIdx) {
+				remainingLetters = append(remainingLetters, letter)
+			}
+		}
+		return fragmentLetters, remainingLetters
+	}
+	return nil, letters
+}
+
+func containsInt(values []int, value int) bool {
+	for _, containedValue := range values {
+		if containedValue == value {
+			return true
+		}
+	}
+	return false
+}
+
+func SortLetters(letters []Letter, mode string) ([]Letter, error) {
+	// Return the letters arranged according to one of the HAND_SORT_MODES
+	// Guarantees:
+	// - The same letters always come back in the same order
+	// - The given slice is not changed
+	// - Return an error for an unknown mode
+
+	if !IsHandSortMode(mode) {
+		return nil, errors.New("Unknown sort mode " + mode + ". Known are: " + strings.Join(HAND_SORT_MODES, ", "))
+	}
+
+	sortedLetters := append([]Letter{}, letters...)
+	if mode != HAND_SORT_MODE_FRAGMENTS {
+		sortLettersByMode(sortedLetters, mode)
+		return sortedLetters, nil
+	}
+
+	// The letters between prefix and suffix stay in alphabetical order.
+	// Suffixes are taken first, so a letter that could belong to either,
+	// e.g. the E of -ED and DE-, goes to the suffix.
+	sortLettersByMode(sortedLetters, HAND_SORT_MODE_ALPHABETICAL)
+	suffixLetters, remainingLetters := takeWordFragment(sortedLetters, WORD_FRAGMENT_SUFFIXES)
+	prefixLetters, remainingLetters := takeWordFragment(remainingLetters, WORD_FRAGMENT_PREFIXES)
+
+	sortedLetters = append(prefixLetters, remainingLetters...)
+	return append(sortedLetters, suffixLetters...), nil
+}
+
+func (player *Player) SortHandByMode(mode string) error {
+	// Arrange the letters in the player's hand according
+	// to one of the HAND_SORT_MODES
+
+	sortedLetters, err := SortLetters(player.LettersInHand, mode)
+	if err != nil {
+		return err
+	}
+	player.LettersInHand = sortedLetters
+	return nil
+}
+
+func validatePreferredHandSortMode(mode string) error {
+	if mode != "" && !IsHandSortMode(mode) {
+		return errors.New("Unknown sort mode " + mode + ". Known are: " + strings.Join(HAND_SORT_MODES, ", "))
+	}
+	return nil
+}
+
+func (player *Player) SetPreferredHandSortMode(mode string) error {
+	// Remember the mode in which the player's hand is arranged
+	// after every refill in this game, empty to keep the order of drawing.
+	// See SetPreferredHandSortMode of the accounts for all new games.
+
+	err := validatePreferredHandSortMode(mode)
+	if err != nil {
+		return err
+	}
+	player.PreferredHandSortMode = mode
+	return nil
+}
+
+func (player *Player) applyPreferredHandSortMode() {
+	if player.PreferredHandSortMode != "" {
+		player.SortHandByMode(player.PreferredHandSortMode)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+func mockLettersFromString(characters string) []Letter {
+	var letters []Letter
+	for characterIdx, character := range characters {
+		letters = append(letters, Letter{
+			Id:         strconv.Itoa(characterIdx),
+			Character:  character,
+			Attributes: letterDistribution[character],
+		})
+	}
+	return letters
+}
+
+func lettersToString(letters []Letter) string {
+	var characters []rune
+	for _, letter := range letters {
+		characters = append(characters, letter.Character)
+	}
+	return string(characters)
+}
+
+func TestSortLettersByMode(t *testing.T) {
+
+	testCases := []struct {
+		letters  string
+		mode     string
+		expected string
+	}{
+		{"zq*eaqb", HAND_SORT_MODE_ALPHABETICAL, "abeqqz*"},
+		{"zq*eaqb", HAND_SORT_MODE_POINTS, "qqzbae*"},
+		{"zq*eaqb", HAND_SORT_MODE_VOWELS_FIRST, "aebqqz*"},
+		{"zq*eaqb", HAND_SORT_MODE_WILDCARDS_FIRST, "*abeqqz"},
+		{"gxnreia", HAND_SORT_MODE_FRAGMENTS, "reaxing"},
+		{"desn*ie", HAND_SORT_MODE_FRAGMENTS, "den*ies"},
+	}
+
+	for _, testCase := range testCases {
+		letters := mockLettersFromString(testCase.letters)
+		sortedLetters, err := SortLetters(letters, testCase.mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sorted := lettersToString(sortedLetters); sorted != testCase.expected {
+			t.Errorf("Expected %s sorted by %s to be %s, Was: %s",
+				testCase.letters, testCase.mode, testCase.expected, sorted)
+		}
+		if unchanged := lettersToString(letters); unchanged != testCase.letters {
+			t.Errorf("Expected the given letters to stay unchanged, Was: %s", unchanged)
+		}
+	}
+
+	_, err := SortLetters(mockLettersFromString("abc"), "random")
+	if err == nil {
+		t.Error("Expected an unknown sort mode to be rejected")
+	}
+}
+
+func TestPreferredHandSortModeIsTakenFromAccount(t *testing.T) {
+
+	useTemporaryStorage(t)
+
+	account, err := RegisterAccount("sorter", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SetPreferredHandSortMode(account.Id, "unknown"); err == nil {
+		t.Error("Expected an unknown sort mode to be rejected")
+	}
+	if err := SetPreferredHandSortMode(account.Id, HAND_SORT_MODE_ALPHABETICAL); err != nil {
+		t.Fatal(err)
+	}
+
+	game := &Game{LetterSet: mockLettersFromString("abcdefghijklmn")}
+	for _, seat := range []Seat{{AccountId: account.Id}, {GuestName: "guest"}} {
+		if err := AddPlayer(seat, game); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := assertEquals(HAND_SORT_MODE_ALPHABETICAL, game.Players[0].PreferredHandSortMode); err != nil {
+		t.Error(err)
+	}
+	if err := assertEquals("", game.Players[1].PreferredHandSortMode); err != nil {
+		t.Error(err)
+	}
+}
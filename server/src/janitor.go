@@ -0,0 +1,310 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Directory in which finished games are kept once they
+// have been moved out of memory, one compressed file per game
+var GAMES_ARCHIVE_DIRECTORY = "gole_archive"
+
+const ARCHIVED_GAME_FILE_EXTENSION = ".gob.gz"
+
+// How often the janitor looks for games to archive or expire
+var JANITOR_INTERVAL = time.Hour
+
+// Time a finished game stays in memory so that
+// the players can still look at the final board
+var FINISHED_GAME_GRACE_PERIOD = 24 * time.Hour
+
+// Time without any activity after which a game
+// that is not finished is removed
+var IDLE_GAME_TTL = 30 * 24 * time.Hour
+
+// Time before the removal of an idle game at which
+// its participants are warned
+var IDLE_GAME_EXPIRY_WARNING_PERIOD = 24 * time.Hour
+
+type GameExpiryEventData struct {
+	// When the game is removed unless something happens in it
+	ExpiresAt time.Time
+}
+
+type archivedGameIndexEntry struct {
+	summary GameSummary
+
+	// Words played in the game in upper case, see GameListFilter.PlayedWord
+	playedWords map[string]bool
+}
+
+// What is needed to find and list the archived games
+// without reading them from the archive, by game Id
+var archivedGameIndex = make(map[string]archivedGameIndexEntry)
+var archivedGameIndexMutex sync.RWMutex
+
+type JanitorSweepStatistics struct {
+	ArchivedGames  int
+	WarnedGames    int
+	ExpiredGames   int
+	RemainingGames int
+	Duration       time.Duration
+}
+
+func StartJanitor() {
+	// Sweep the games every JANITOR_INTERVAL in the background
+	go func() {
+		for range time.Tick(JANITOR_INTERVAL) {
+			SweepGames()
+		}
+	}()
+}
+
+func getArchivedGameFilePath(gameId string) string {
+	return filepath.Join(GAMES_ARCHIVE_DIRECTORY, gameId+ARCHIVED_GAME_FILE_EXTENSION)
+}
+
+func ArchiveGame(game *Game) error {
+	// Move a finished game from memory into the GAMES_ARCHIVE_DIRECTORY
+	// Requires:
+	// - The game to be acquired, see Acquire
+	// Guarantees:
+	// - The game can still be found by GetReadableGameByUUID, ListGames
+	//   and ForEachFinishedGame, but it cannot be changed anymore
+	// - Return an error if the game is not finished or could not be stored,
+	//   in which case it is kept in memory
+
+	if !game.GameOver {
+		return errors.New("Only finished games can be archived.")
+	}
+
+	err := os.MkdirAll(GAMES_ARCHIVE_DIRECTORY, 0755)
+	if err != nil {
+		return err
+	}
+
+	err = writeCompressedGobFile(getArchivedGameFilePath(game.Id), game)
+	if err != nil {
+		return err
+	}
+
+	indexArchivedGame(game)
+	removeGameFromMemory(game)
+	return nil
+}
+
+func GetArchivedGame(gameId string) (*Game, error) {
+	// Return a finished game that has been archived
+
+	// Ids are generated by the server, anything else
+	// must not be used to build a file path
+	if gameId == "" || strings.ContainsAny(gameId, `/\.`) {
+		return nil, errors.New("Game with uuid " + gameId + " could not be found!")
+	}
+
+	var game Game
+	isArchived, err := readCompressedGobFile(getArchivedGameFilePath(gameId), &game)
+	if err != nil {
+		return nil, err
+	}
+	if !isArchived {
+		return nil, errors.New("Game with uuid " + gameId + " could not be found!")
+	}
+	game.isReadOnly = true
+	return &game, nil
+}
+
+func indexArchivedGame(game *Game) {
+	// Add a game that has been archived to the archivedGameIndex
+
+	entry := archivedGameIndexEntry{
+		summary:     game.GetSummary(),
+		playedWords: make(map[string]bool),
+	}
+	for _, turn := range game.Turns {
+		if turn.Type != TURN_TYPE_PLAY {
+			continue
+		}
+		for _, playedWord := range turn.Words {
+			entry.playedWords[strings.ToUpper(playedWord.Word)] = true
+		}
+	}
+
+	archivedGameIndexMutex.Lock()
+	archivedGameIndex[game.Id] = entry
+	archivedGameIndexMutex.Unlock()
+}
+
+func (entry *archivedGameIndexEntry) hasPlayedWord(word string) bool {
+	return entry.playedWords[strings.ToUpper(word)]
+}
+
+func isArchivedGame(gameId string) bool {
+	archivedGameIndexMutex.RLock()
+	defer archivedGameIndexMutex.RUnlock()
+	_, isArchived := archivedGameIndex[gameId]
+	return isArchived
+}
+
+func getArchivedGameIndexEntries() []archivedGameIndexEntry {
+	// Return a copy of the archivedGameIndex that can be walked through
+	// without holding the archivedGameIndexMutex
+
+	archivedGameIndexMutex.RLock()
+	defer archivedGameIndexMutex.RUnlock()
+
+	entries := make([]archivedGameIndexEntry, 0, len(archivedGameIndex))
+	for _, entry := range archivedGameIndex {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func LoadArchivedGameIndex() {
+	// Read every archived game once to build the archivedGameIndex
+	ForEachArchivedGame(indexArchivedGame)
+
+	archivedGameIndexMutex.RLock()
+	log.Printf("Indexed %d archived games from %s", len(archivedGameIndex), GAMES_ARCHIVE_DIRECTORY)
+	archivedGameIndexMutex.RUnlock()
+}
+
+func ForEachArchivedGame(handleGame func(game *Game)) {
+	// Call the given function once for every archived game.
+	// Games that cannot be read are logged and skipped.
+	// Reads the whole archive, see the archivedGameIndex for
+	// finding archived games instead.
+
+	archiveFiles, err := os.ReadDir(GAMES_ARCHIVE_DIRECTORY)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Println("Could not read games archive: ", err)
+		}
+		return
+	}
+
+	for _, archiveFile := range archiveFiles {
+		if !strings.HasSuffix(archiveFile.Name(), ARCHIVED_GAME_FILE_EXTENSION) {
+			continue
+		}
+		game, err := GetArchivedGame(strings.TrimSuffix(archiveFile.Name(), ARCHIVED_GAME_FILE_EXTENSION))
+		if err != nil {
+			log.Println("Could not read archived game: ", err)
+			continue
+		}
+		handleGame(game)
+	}
+}
+
+func removeGameFromMemory(game *Game) {
+	// Forget a game that has been archived or has expired
+	// along with its stored state
+	// Requires:
+	// - The game to be acquired, see Acquire
+	// Guarantees:
+	// - Requests that have been waiting for the game meanwhile
+	//   cannot store it again, see isReadOnly
+
+	game.isReadOnly = true
+
+	gamesMutex.Lock()
+	var remainingGames []*Game
+	for _, gameInMemory := range games {
+		if gameInMemory.Id != game.Id {
+			remainingGames = append(remainingGames, gameInMemory)
+		}
+	}
+	games = remainingGames
+	gamesMutex.Unlock()
+
+	gameChangeNotifiersMutex.Lock()
+	delete(gameChangeNotifiers, game.Id)
+	gameChangeNotifiersMutex.Unlock()
+
+	err := deleteStoredGame(game.Id)
+	if err != nil {
+		log.Printf("Could not delete stored game %s: %s", game.Id, err)
+	}
+}
+
+func SweepGames() JanitorSweepStatistics {
+	// Keep the games in memory from growing without bound
+	// Guarantees:
+	// - Games that have been finished for longer than the
+	//   FINISHED_GAME_GRACE_PERIOD are archived
+	// - Participants of games that have been idle for nearly IDLE_GAME_TTL
+	//   are warned IDLE_GAME_EXPIRY_WARNING_PERIOD before the game expires
+	// - Games that have still been idle after the warning period
+	//   are removed for good. Tournament games are never removed
+	//   since the tournament still needs their results.
+	// - Return and log what has been done
+
+	startTime := time.Now().UTC()
+	var statistics JanitorSweepStatistics
+
+	// Games are removed from the slice while it is walked through
+	for _, game := range getGamesInMemory() {
+		sweepGame(game, startTime, &statistics)
+	}
+
+	statistics.RemainingGames = len(getGamesInMemory())
+	statistics.Duration = time.Since(startTime)
+	log.Printf("Janitor sweep: archived %d, warned %d, expired %d, %d games left in memory (took %s)",
+		statistics.ArchivedGames, statistics.WarnedGames, statistics.ExpiredGames,
+		statistics.RemainingGames, statistics.Duration)
+
+	return statistics
+}
+
+func sweepGame(game *Game, startTime time.Time, statistics *JanitorSweepStatistics) {
+	// Archive, warn about or expire a single game, see SweepGames
+
+	game.Acquire()
+	defer game.Release()
+
+	idleTime := startTime.Sub(game.LastActivityAt)
+
+	if game.GameOver {
+		if idleTime < FINISHED_GAME_GRACE_PERIOD {
+			return
+		}
+		err := ArchiveGame(game)
+		if err != nil {
+			log.Printf("Could not archive game %s: %s", game.Id, err)
+			return
+		}
+		statistics.ArchivedGames++
+		return
+	}
+
+	if game.TournamentId != "" || game.LastActivityAt.IsZero() {
+		return
+	}
+
+	if idleTime < IDLE_GAME_TTL-IDLE_GAME_EXPIRY_WARNING_PERIOD {
+		return
+	}
+
+	// The warning does not count as activity, see publishEvent
+	if game.ExpiryWarningSentAt.Before(game.LastActivityAt) {
+		game.ExpiryWarningSentAt = startTime
+		game.PublishChange(GAME_EVENT_EXPIRY_WARNING, GameExpiryEventData{
+			ExpiresAt: startTime.Add(IDLE_GAME_EXPIRY_WARNING_PERIOD),
+		})
+		statistics.WarnedGames++
+		return
+	}
+
+	if startTime.Sub(game.ExpiryWarningSentAt) < IDLE_GAME_EXPIRY_WARNING_PERIOD {
+		return
+	}
+
+	game.PublishChange(GAME_EVENT_GAME_EXPIRED, nil)
+	removeGameFromMemory(game)
+	statistics.ExpiredGames++
+}
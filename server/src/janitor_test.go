@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestArchivedGamesAreIndexedAndReadOnly(t *testing.T) {
+
+	useTemporaryStorage(t)
+	gamesBeforeTest, indexBeforeTest := games, archivedGameIndex
+	archivedGameIndex = make(map[string]archivedGameIndexEntry)
+	t.Cleanup(func() { games, archivedGameIndex = gamesBeforeTest, indexBeforeTest })
+
+	archivedGames := []*Game{
+		{Id: "archived", GameOver: true, Players: []Player{{Name: "a", AccountId: "account"}, {Name: "b"}},
+			Turns: []TurnRecord{
+				{Type: TURN_TYPE_PLAY, Words: []PlayedWord{{Word: "cat"}}},
+				{Type: TURN_TYPE_WITHDRAWN, Words: []PlayedWord{{Word: "zzz"}}},
+			}},
+		{Id: "other", GameOver: true, Players: []Player{{Name: "c", AccountId: "other"}, {Name: "d"}}},
+	}
+	games = append([]*Game{}, archivedGames...)
+	for _, game := range archivedGames {
+		game.Acquire()
+		err := ArchiveGame(game)
+		game.Release()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(games) != 0 {
+		t.Errorf("Expected the archived games to be removed from memory, Was: %d games", len(games))
+	}
+
+	if _, err := GetGameByUUID("archived"); err == nil {
+		t.Error("Expected an archived game not to be handed out for changes")
+	}
+	game, err := GetReadableGameByUUID("archived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	GetGameChangeNotifier(game)
+	if _, exists := gameChangeNotifiers["archived"]; exists {
+		t.Error("Expected no notifier to be kept for an archived game")
+	}
+	if err := game.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(getStoredGameFilePath("archived")); !os.IsNotExist(err) {
+		t.Errorf("Expected an archived game not to be stored again, Was: %v", err)
+	}
+
+	for _, testCase := range []struct {
+		playedWord      string
+		expectedGameIds []string
+	}{
+		{"CAT", []string{"archived"}},
+		{"zzz", []string{}},
+	} {
+		page, err := ListGames(GameListFilter{PlayedWord: testCase.playedWord}, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Games) != len(testCase.expectedGameIds) ||
+			(len(page.Games) > 0 && page.Games[0].Id != testCase.expectedGameIds[0]) {
+			t.Errorf("Expected the games %v for the word %s, Was: %+v",
+				testCase.expectedGameIds, testCase.playedWord, page.Games)
+		}
+	}
+
+	var finishedGameIds []string
+	ForEachFinishedGame("account", func(game *Game) {
+		finishedGameIds = append(finishedGameIds, game.Id)
+	})
+	if len(finishedGameIds) != 1 || finishedGameIds[0] != "archived" {
+		t.Errorf("Expected only the account's archived game, Was: %v", finishedGameIds)
+	}
+
+	archivedGameIndex = make(map[string]archivedGameIndexEntry)
+	LoadArchivedGameIndex()
+	if !isArchivedGame("archived") || !isArchivedGame("other") {
+		t.Errorf("Expected the index to be rebuilt from the archive, Was: %v", archivedGameIndex)
+	}
+}
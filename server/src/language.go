@@ -7,8 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"gole/golelibs"
-	"math/rand"
-	"time"
+	"sort"
+	"strings"
 )
 
 type LetterAttributes struct {
@@ -19,10 +19,53 @@ type Letter struct {
 	Id         string
 	Character  rune
 	Attributes LetterAttributes
+
+	// The letter a wildcard letter stands for on the board, 0 otherwise.
+	// The Character of a wildcard letter always stays the WILDCARD_CHARACTER.
+	DesignatedCharacter rune `json:",omitempty"`
 }
 
 const WILDCARD_CHARACTER rune = '*'
 
+func (letter Letter) IsWildcard() bool {
+	return letter.Character == WILDCARD_CHARACTER
+}
+
+func (letter Letter) GetPlayedCharacter() rune {
+	// Return the character the letter counts as in words on the board
+	if letter.IsWildcard() && letter.DesignatedCharacter != 0 {
+		return letter.DesignatedCharacter
+	}
+	return letter.Character
+}
+
+func (letter *Letter) DesignateWildcard(character rune) error {
+	// Let a wildcard letter stand for the given character
+	// Guarantees:
+	// - A wildcard letter can be designated again as often as needed
+	// - Return an error if the letter is no wildcard letter or
+	//   if the character is not a letter of the alphabet
+
+	if !letter.IsWildcard() {
+		return errors.New("Cannot replace letter on non-wildcard letter.")
+	}
+
+	_, isInAlphabet := letterDistribution[character]
+	if !isInAlphabet || character == WILDCARD_CHARACTER {
+		return errors.New("A wildcard letter needs to be replaced with an actual letter.")
+	}
+
+	letter.DesignatedCharacter = character
+	return nil
+}
+
+// Name of the ruleset, i.e. the combination of dictionary and
+// letter distribution, that is defined in this file.
+// Ratings are kept separately for each ruleset.
+const DEFAULT_RULESET = "english"
+
+var SUPPORTED_RULESETS = []string{DEFAULT_RULESET}
+
 var lettersAmount = 100
 var letterDistribution = map[rune]LetterAttributes{
 	WILDCARD_CHARACTER: {2, 0},
@@ -54,11 +97,34 @@ var letterDistribution = map[rune]LetterAttributes{
 	'z':                {1, 10},
 }
 
+var VOWELS = "aeiou"
+
+func IsVowel(character rune) bool {
+	return strings.ContainsRune(VOWELS, character)
+}
+
 func GetFullLetterSet() ([]Letter, error) {
 	/* Return a randomly shuffled full initial set of letters. */
+	return GetFullLetterSetShuffledBy(getRandomIntn)
+}
+
+func GetFullLetterSetShuffledBy(randomIntn func(int) int) ([]Letter, error) {
+	/* Return a full initial set of letters shuffled with the given
+	   source of random numbers in [0, n), see getRandomPermutation. */
+
+	// The letters are put together in alphabetical order since the
+	// order of a map is not fixed, the same random numbers must
+	// always lead to the same letter set
+	var letters []rune
+	for letter := range letterDistribution {
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+
 	var fullLetterSet []Letter
 	letterCount := 0
-	for letter, letterProperties := range letterDistribution {
+	for _, letter := range letters {
+		letterProperties := letterDistribution[letter]
 		letterCount += letterProperties.Occurrences
 		for i := 0; i < letterProperties.Occurrences; i++ {
 			letterStruct, err := GetLetterStructFromRune(letter)
@@ -72,8 +138,7 @@ func GetFullLetterSet() ([]Letter, error) {
 		return []Letter{Letter{}}, errors.New(fmt.Sprintf("Letter distribution error! Is %d, expected %d\n", letterCount, lettersAmount))
 	}
 	// Shuffle string
-	rand.Seed(time.Now().UTC().UnixNano())
-	randomIndexes := rand.Perm(letterCount)
+	randomIndexes := getRandomPermutation(letterCount, randomIntn)
 	var fullShuffledLetterSet []Letter = make([]Letter, letterCount)
 	for originalIndex, newRandomIndex := range randomIndexes {
 		fullShuffledLetterSet[newRandomIndex] = fullLetterSet[originalIndex]
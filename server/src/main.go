@@ -6,9 +6,56 @@ import (
 	"gole/golelibs"
 	"log"
 	"strings"
+	"sync"
+	"time"
 )
 
-var games []Game
+// Games that are kept in memory, i.e. that are not archived.
+// The gamesMutex only guards the slice itself, never hold it while
+// acquiring a game since the games acquire it on their own.
+var games []*Game
+var gamesMutex sync.RWMutex
+
+func getGamesInMemory() []*Game {
+	// Return a copy of the games in memory that can be walked through
+	// without holding the gamesMutex
+	gamesMutex.RLock()
+	defer gamesMutex.RUnlock()
+	return append([]*Game{}, games...)
+}
+
+// Every game is guarded by a mutex of its own that has to be held
+// while the game is read or changed. The mutexes are kept outside of
+// the Game struct so that a game that is rolled back to a clone of
+// itself keeps its lock. They are never removed, since a request may
+// still hold a game that has been archived or has expired meanwhile.
+var gameMutexes = make(map[string]*sync.Mutex)
+var gameMutexesMutex sync.Mutex
+
+func getGameMutex(gameId string) *sync.Mutex {
+	gameMutexesMutex.Lock()
+	defer gameMutexesMutex.Unlock()
+
+	mutex, exists := gameMutexes[gameId]
+	if !exists {
+		mutex = &sync.Mutex{}
+		gameMutexes[gameId] = mutex
+	}
+	return mutex
+}
+
+// The methods are not called Lock and Unlock because vet would
+// then report every copy of a game, e.g. when it is rolled back
+
+func (game *Game) Acquire() {
+	// Wait until no one else reads or changes the game
+	getGameMutex(game.Id).Lock()
+}
+
+func (game *Game) Release() {
+	// Let others read and change the game again
+	getGameMutex(game.Id).Unlock()
+}
 
 func init() {}
 
@@ -18,42 +65,97 @@ func GetGameByUUID(uuid string) (*Game, error) {
 	// - a lower letter standard unix uuid as created for the games
 	// Guarantees:
 	// - Return reference to game struct that has uuid set as game id
+	// - Return an error for archived games since they cannot be
+	//   changed anymore, see GetReadableGameByUUID
 	// - log fatal if no game in array has the given uuid
+	gamesMutex.RLock()
+	defer gamesMutex.RUnlock()
+
 	for idx, _ := range games {
 		if games[idx].Id == strings.TrimSpace(uuid) {
-			return &games[idx], nil
+			return games[idx], nil
 		}
 	}
+	if isArchivedGame(strings.TrimSpace(uuid)) {
+		return &Game{}, errors.New("The game has been archived and can no longer be changed.")
+	}
 	return &Game{}, errors.New("Game with uuid " + uuid + " could not be found!")
 }
 
-func StartNewGame(playerNames ...string) (string, error) {
+func GetReadableGameByUUID(uuid string) (*Game, error) {
+	// Return the game with the given ID for requests that only read it
+	// Guarantees:
+	// - Finished games that have been archived are read from the archive.
+	//   Changes to them are never stored, see isReadOnly.
+	game, err := GetGameByUUID(uuid)
+	if err == nil || !isArchivedGame(strings.TrimSpace(uuid)) {
+		return game, err
+	}
+	return GetArchivedGame(strings.TrimSpace(uuid))
+}
+
+func StartNewGame(options GameOptions, creatorAccountId string, seats ...Seat) (string, error) {
+	// Initiate a new game outside of tournaments, see startNewGame
+	return startNewGame(options, creatorAccountId, "", seats...)
+}
+
+func startNewGame(options GameOptions, creatorAccountId string, tournamentId string, seats ...Seat) (string, error) {
 	// Initiate a new game
 	// Requires:
-	// - A list of player names (2-4 players are legal)
+	// - The options for the game
+	// - The Id of the account creating the game, empty for guests
+	// - The Id of the tournament the game is part of, empty if none
+	// - A list of seats (2-4 players are legal), each either
+	//   linked to an account or taken by a guest with a throwaway name.
+	//   In team games the seats are grouped into teams by their TeamName.
 	// Guarantees:
 	// - Creates a new game object and adds the players
 	// - Trow an error if the number of players is illegal
+	//   or if a seat could not be taken
 	// - Return the uuid of the game if successful
 
-	if len(playerNames) < 2 || len(playerNames) > 4 {
-		return "", errors.New(fmt.Sprintf("%d is not a legal amount of players. Needs to be 2-3.", len(playerNames)))
+	if len(seats) < MIN_NUMBER_OF_PLAYERS || len(seats) > MAX_NUMBER_OF_PLAYERS {
+		return "", errors.New(fmt.Sprintf("%d is not a legal amount of players. Needs to be %d-%d.",
+			len(seats), MIN_NUMBER_OF_PLAYERS, MAX_NUMBER_OF_PLAYERS))
+	}
+
+	err := options.Validate()
+	if err != nil {
+		return "", err
 	}
 
 	game := &Game{}
 	game.Id = golelibs.GetNewUUID()
+	game.Options = options
+	game.CreatorAccountId = creatorAccountId
+	game.TournamentId = tournamentId
+	game.CreatedAt = time.Now().UTC()
+	game.LastActivityAt = game.CreatedAt
 
 	// Letter set needs to be generated before Players are added
 	// since letters need to be taken off the set.
-	var err error
-	game.LetterSet, err = GetFullLetterSet()
+	if options.IsSeeded {
+		game.Random = NewSeededRandom(options.Seed)
+	}
+	game.LetterSet, err = GetFullLetterSetShuffledBy(game.getRandomIntn)
 	if err != nil {
 		return "", err
 	}
+	game.commitToLetterSet()
 
-	for _, playerName := range playerNames {
-		log.Printf("Add player %s to Game %s\n", playerName, game.Id)
-		AddPlayer(playerName, game)
+	if game.IsTeamGame() {
+		err = AddTeams(seats, game)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		for _, seat := range seats {
+			log.Printf("Add player %s%s to Game %s\n", seat.AccountId, seat.GuestName, game.Id)
+			err = AddPlayer(seat, game)
+			if err != nil {
+				return "", err
+			}
+		}
 	}
 
 	game.Tiles = GetCleanTiles()
@@ -63,12 +165,44 @@ func StartNewGame(playerNames ...string) (string, error) {
 
 	//First player in slice will have first turn
 	game.PlayerIdxWithTurn = 0
+	game.startTurnClock()
+
+	if game.IsDuplicateGame() {
+		game.StartDuplicateRound(nil)
+	}
+
+	// Nobody else knows the game before it is added
+	// to the games, so it does not need to be locked
+	err = game.Save()
+	if err != nil {
+		log.Println("Could not persist game: ", err)
+	}
 
-	games = append(games, *game)
+	gamesMutex.Lock()
+	games = append(games, game)
+	gamesMutex.Unlock()
 
 	return game.Id, nil
 }
 
 func main() {
+	err := LoadGames()
+	if err != nil {
+		log.Fatal("Could not load stored games: ", err)
+	}
+	LoadArchivedGameIndex()
+	err = LoadAccounts()
+	if err != nil {
+		log.Fatal("Could not load stored accounts: ", err)
+	}
+	err = LoadRatings()
+	if err != nil {
+		log.Fatal("Could not load stored ratings: ", err)
+	}
+	err = LoadTournaments()
+	if err != nil {
+		log.Fatal("Could not load stored tournaments: ", err)
+	}
+	StartJanitor()
 	StartWebServer()
 }
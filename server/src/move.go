@@ -0,0 +1,402 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gole/golelibs"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Directions in which a word can be laid out on the board
+const (
+	MOVE_DIRECTION_HORIZONTAL = "horizontal"
+	MOVE_DIRECTION_VERTICAL   = "vertical"
+)
+
+type MovePlacement struct {
+	LetterId      string
+	VerticalIdx   int
+	HorizontalIdx int
+
+	// Letter that a wildcard letter stands for, ignored for other letters
+	WildcardCharacter rune
+}
+
+type Move struct {
+	// The letters from the hand to put on the board
+	Placements []MovePlacement
+
+	// Alternatively to Placements the word to lay out from the start tile
+	// in the given direction, including letters that are on the board already
+	StartVerticalIdx   int
+	StartHorizontalIdx int
+	Direction          string
+	Word               string
+
+	// Positions within the Word that are to be played with a wildcard letter.
+	// A wildcard letter is also used if there is no other letter left for a position.
+	WildcardIdxs []int
+
+	// Positions within the Word that have to be on the board already
+	BoardLetterIdxs []int
+
+	// Alternatively to all of the above the move in standard notation,
+	// e.g. "8G C(A)t", see notation.go
+	Notation string
+}
+
+type LetterPlacement struct {
+	VerticalIdx   int
+	HorizontalIdx int
+	Letter        Letter
+}
+
+type EvaluatedMove struct {
+	Words  []PlayedWord
+	Points int
+}
+
+func (game *Game) EvaluatePlacements(placements []LetterPlacement) (EvaluatedMove, error) {
+	// Score a move as if it was played on the board as it is now
+	// Requires:
+	// - The letters to place with their positions, wildcard letters
+	//   already replaced with an actual letter
+	// Guarantees:
+	// - The move is played on a copy of the game
+	//   so the game itself is never changed
+	// - Return the words the move forms and the points it is worth
+	// - Return an error if a placement is illegal or if the move
+	//   does not form valid words
+
+	evaluationGame, err := game.Clone()
+	if err != nil {
+		return EvaluatedMove{}, err
+	}
+
+	for _, placement := range placements {
+		err = evaluationGame.putLetterOnTile(placement.VerticalIdx, placement.HorizontalIdx, placement.Letter)
+		if err != nil {
+			return EvaluatedMove{}, err
+		}
+	}
+
+	playedWords, points, err := evaluationGame.ScoreNewWords(true)
+	if err != nil {
+		return EvaluatedMove{}, err
+	}
+
+	return EvaluatedMove{Words: playedWords, Points: points}, nil
+}
+
+func (game *Game) getPlacementsForWord(move Move) ([]MovePlacement, error) {
+	// Work out which letters of the hand of the player with the turn
+	// lay out the move's word
+	// Guarantees:
+	// - Positions that are occupied already need to show the
+	//   word's letter there and are skipped
+	// - Return an error if the word does not fit on the board, if it
+	//   would run into letters before or after it or if the hand lacks
+	//   a letter
+
+	word := strings.ToLower(strings.TrimSpace(move.Word))
+	if word == "" {
+		return nil, errors.New("A move needs either placements or a word.")
+	}
+
+	verticalStep, horizontalStep := 0, 1
+	if move.Direction == MOVE_DIRECTION_VERTICAL {
+		verticalStep, horizontalStep = 1, 0
+	} else if move.Direction != MOVE_DIRECTION_HORIZONTAL {
+		return nil, errors.New("Unknown direction " + move.Direction)
+	}
+
+	isOccupied := func(verticalIdx int, horizontalIdx int) bool {
+		return AreValidBoardCoordinates(verticalIdx, horizontalIdx) &&
+			game.Tiles[verticalIdx][horizontalIdx].Letter != (Letter{})
+	}
+	wordLength := utf8.RuneCountInString(word)
+	if isOccupied(move.StartVerticalIdx-verticalStep, move.StartHorizontalIdx-horizontalStep) ||
+		isOccupied(move.StartVerticalIdx+wordLength*verticalStep, move.StartHorizontalIdx+wordLength*horizontalStep) {
+		return nil, errors.New("The word must include all letters directly before and after it.")
+	}
+
+	isWildcardIdx := make(map[int]bool)
+	for _, wildcardIdx := range move.WildcardIdxs {
+		isWildcardIdx[wildcardIdx] = true
+	}
+	isBoardLetterIdx := make(map[int]bool)
+	for _, boardLetterIdx := range move.BoardLetterIdxs {
+		isBoardLetterIdx[boardLetterIdx] = true
+	}
+
+	usedLetterIds := make(map[string]bool)
+	takeLetterFromHand := func(character rune) (Letter, bool) {
+		for _, letter := range game.Players[game.PlayerIdxWithTurn].LettersInHand {
+			if !usedLetterIds[letter.Id] && letter.Character == character {
+				usedLetterIds[letter.Id] = true
+				return letter, true
+			}
+		}
+		return Letter{}, false
+	}
+
+	var placements []MovePlacement
+	characterIdx := 0
+	for _, character := range word {
+		verticalIdx := move.StartVerticalIdx + characterIdx*verticalStep
+		horizontalIdx := move.StartHorizontalIdx + characterIdx*horizontalStep
+
+		if !AreValidBoardCoordinates(verticalIdx, horizontalIdx) {
+			return nil, errors.New("The word does not fit on the board.")
+		}
+
+		if isOccupied(verticalIdx, horizontalIdx) {
+			if game.Tiles[verticalIdx][horizontalIdx].Letter.GetPlayedCharacter() != character {
+				return nil, errors.New(fmt.Sprintf(
+					"The word does not match the letter on the board at %d/%d.", verticalIdx, horizontalIdx))
+			}
+			characterIdx++
+			continue
+		}
+
+		if isBoardLetterIdx[characterIdx] {
+			return nil, errors.New(fmt.Sprintf(
+				"There is no letter on the board at %d/%d.", verticalIdx, horizontalIdx))
+		}
+
+		placement := MovePlacement{VerticalIdx: verticalIdx, HorizontalIdx: horizontalIdx}
+		letter, hasLetter := Letter{}, false
+		if !isWildcardIdx[characterIdx] {
+			letter, hasLetter = takeLetterFromHand(character)
+		}
+		if !hasLetter {
+			letter, hasLetter = takeLetterFromHand(WILDCARD_CHARACTER)
+			placement.WildcardCharacter = character
+		}
+		if !hasLetter {
+			return nil, errors.New(fmt.Sprintf("There is no letter %c left in the hand.", character))
+		}
+		placement.LetterId = letter.Id
+
+		placements = append(placements, placement)
+		characterIdx++
+	}
+
+	return placements, nil
+}
+
+func (game *Game) getPlacementsForMove(move Move) ([]MovePlacement, error) {
+	// Return the placements of the move in whichever form it has been given
+
+	if move.Notation != "" {
+		notatedMove, err := ParseMoveNotation(move.Notation)
+		if err != nil {
+			return nil, err
+		}
+		return game.getPlacementsForWord(notatedMove)
+	}
+
+	if len(move.Placements) > 0 {
+		return move.Placements, nil
+	}
+	return game.getPlacementsForWord(move)
+}
+
+func (game *Game) placeMovePlacements(placements []MovePlacement) error {
+	// Put the letters of a move from the hand of the player with
+	// the turn onto the board, designating wildcard letters as given
+	// Guarantees:
+	// - If the center tile is still empty, the letter for it is placed first
+	// - Nothing is published
+
+	placements = append([]MovePlacement{}, placements...)
+	sort.SliceStable(placements, func(i, j int) bool {
+		return TileIsCenterTile(placements[i].VerticalIdx, placements[i].HorizontalIdx) &&
+			!TileIsCenterTile(placements[j].VerticalIdx, placements[j].HorizontalIdx)
+	})
+
+	player := &game.Players[game.PlayerIdxWithTurn]
+	for _, placement := range placements {
+		letter, err := player.GetLetterFromHandById(placement.LetterId)
+		if err != nil {
+			return err
+		}
+		var wildcardCharacter rune
+		if letter.IsWildcard() {
+			wildcardCharacter = placement.WildcardCharacter
+		}
+
+		err = game.placeLetterFromHand(placement.VerticalIdx, placement.HorizontalIdx, placement.LetterId, wildcardCharacter)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (game *Game) PlayMove(move Move) (int, []string, error) {
+	// Play a whole move of the player with the turn at once
+	// Requires:
+	// - Either the placements of letters from the hand, a word
+	//   with its start tile and direction or the move's notation
+	// Guarantees:
+	// - Letters that have been placed on the board one by one
+	//   in the current turn are taken back first
+	// - The move is placed and finished just like with
+	//   PlaceLetter and FinishTurn
+	// - If any part of the move fails, the game is left
+	//   exactly as it was before and the error is returned
+	// - Return the points and words like FinishTurn
+
+	if game.GameOver {
+		return -1, nil, errors.New("Cannot play move. Game is over.")
+	}
+
+	if game.IsDuplicateGame() {
+		return -1, nil, errors.New("Moves of duplicate games are submitted for the round.")
+	}
+
+	// The clock can end the turn or the game on its own,
+	// which must not be rolled back with the move
+	err := game.CheckClock()
+	if err != nil {
+		return -1, nil, err
+	}
+
+	// Finishing the turn accepts the previous move,
+	// which is rolled back along with everything else
+	gameBeforeMove, err := game.Clone()
+	if err != nil {
+		return -1, nil, err
+	}
+
+	points, words, err := game.playMove(move)
+	if err != nil {
+		*game = *gameBeforeMove
+		return -1, nil, err
+	}
+	return points, words, nil
+}
+
+func (game *Game) playMove(move Move) (int, []string, error) {
+	err := game.ReturnUnlockedLettersToHand()
+	if err != nil {
+		return -1, nil, err
+	}
+
+	placements, err := game.getPlacementsForMove(move)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	err = game.placeMovePlacements(placements)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	return FinishTurn(game)
+}
+
+type ValidatedWord struct {
+	WordScore
+	IsValid bool
+}
+
+type MoveValidation struct {
+	// Whether the letters can be placed like this, regardless of the words
+	IsLegal bool
+
+	// The move in standard notation, empty if it is not legal
+	Notation string
+
+	// Why the move cannot be played, empty if it can
+	Problems []string
+
+	// The PLACEMENT_ERROR code if the placed letters
+	// do not form one legal move
+	PlacementErrorCode string
+
+	Words []ValidatedWord
+
+	// What the move would be worth
+	Points int
+}
+
+func (game *Game) ValidateMove(move Move) (MoveValidation, error) {
+	// Check and score a move of the player with the turn without playing it
+	// Requires:
+	// - The move as for PlayMove
+	// Guarantees:
+	// - The move is evaluated on a copy of the game
+	//   so the game itself is never changed
+	// - Return the legality of the placements, every word the move
+	//   would form with its validity and a tile by tile score
+	// - Return an error only if the game is in no state to evaluate moves
+
+	if game.GameOver {
+		return MoveValidation{}, errors.New("Cannot validate move. Game is over.")
+	}
+
+	if game.IsDuplicateGame() {
+		return MoveValidation{}, errors.New("Moves of duplicate games are evaluated when they are submitted.")
+	}
+
+	evaluationGame, err := game.Clone()
+	if err != nil {
+		return MoveValidation{}, err
+	}
+
+	validation := MoveValidation{IsLegal: true}
+	addProblem := func(problem string) {
+		validation.Problems = append(validation.Problems, problem)
+	}
+
+	err = evaluationGame.ReturnUnlockedLettersToHand()
+	if err != nil {
+		return MoveValidation{}, err
+	}
+
+	placements, err := evaluationGame.getPlacementsForMove(move)
+	if err == nil {
+		err = evaluationGame.placeMovePlacements(placements)
+	}
+	if err != nil {
+		validation.IsLegal = false
+		addProblem(err.Error())
+		return validation, nil
+	}
+
+	err = evaluationGame.CheckPlacementOfUnlockedLetters()
+	if err != nil {
+		validation.IsLegal = false
+		validation.PlacementErrorCode = GetPlacementErrorCode(err)
+		addProblem(err.Error())
+	}
+
+	if validation.IsLegal {
+		validation.Notation = evaluationGame.GetNotationOfUnlockedLetters()
+	}
+
+	newWordsOnBoard, err := evaluationGame.GetNewWordsFromBoard(false)
+	if err != nil {
+		return MoveValidation{}, err
+	}
+	if len(newWordsOnBoard) == 0 {
+		validation.IsLegal = false
+		addProblem("No new words found on board.")
+	}
+
+	for _, wordOnBoard := range newWordsOnBoard {
+		validatedWord := ValidatedWord{WordScore: wordOnBoard.GetScore()}
+		validatedWord.IsValid = golelibs.IsAValidWord(validatedWord.Word)
+		if !validatedWord.IsValid {
+			addProblem("Not a valid word: " + validatedWord.Word)
+		}
+		validation.Words = append(validation.Words, validatedWord)
+		validation.Points += validatedWord.Points
+	}
+
+	return validation, nil
+}
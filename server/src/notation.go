@@ -0,0 +1,195 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Standard move notation, e.g. "8G C(A)t":
+// - The coordinate names the first tile of the main word. Rows are
+//   numbered from 1 at the top, columns lettered from A at the left.
+//   The row comes first for words played across ("8G"),
+//   the column first for words played down ("G8").
+// - Letters played from the hand are written in upper case,
+//   wildcard letters in lower case
+// - Letters that are on the board already are put in parentheses
+
+func FormatCoordinate(verticalIdx int, horizontalIdx int, direction string) string {
+	// Return the notation of the tile at the given indexes
+	// as the start of a word played in the given direction
+
+	row := strconv.Itoa(verticalIdx + 1)
+	column := string(rune('A' + horizontalIdx))
+	if direction == MOVE_DIRECTION_VERTICAL {
+		return column + row
+	}
+	return row + column
+}
+
+func ParseCoordinate(coordinate string) (int, int, string, error) {
+	// Return the vertical and horizontal index of the tile and the
+	// direction of the word that the coordinate stands for
+	// Guarantees:
+	// - Column letters are accepted in upper and lower case
+	// - Return an error if the coordinate is malformed or off the board
+
+	coordinate = strings.ToUpper(strings.TrimSpace(coordinate))
+	if coordinate == "" {
+		return -1, -1, "", errors.New("Missing coordinate.")
+	}
+
+	direction := MOVE_DIRECTION_HORIZONTAL
+	rowPart, columnPart := strings.TrimRight(coordinate, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"), ""
+	if rowPart == coordinate {
+		direction = MOVE_DIRECTION_VERTICAL
+		columnPart = strings.TrimRight(coordinate, "0123456789")
+		rowPart = coordinate[len(columnPart):]
+	} else {
+		columnPart = coordinate[len(rowPart):]
+	}
+
+	row, err := strconv.Atoi(rowPart)
+	if err != nil || len(columnPart) != 1 {
+		return -1, -1, "", errors.New("Invalid coordinate " + coordinate)
+	}
+
+	verticalIdx := row - 1
+	horizontalIdx := int(columnPart[0] - 'A')
+	if !AreValidBoardCoordinates(verticalIdx, horizontalIdx) {
+		return -1, -1, "", errors.New("Coordinate " + coordinate + " is off the board.")
+	}
+	return verticalIdx, horizontalIdx, direction, nil
+}
+
+func ParseMoveNotation(notation string) (Move, error) {
+	// Turn a move in standard notation, e.g. "8G C(A)t", into a Move
+	// Guarantees:
+	// - Lower case letters become WildcardIdxs and
+	//   letters in parentheses BoardLetterIdxs of the Move
+	// - Return an error if the notation is malformed
+
+	fields := strings.Fields(notation)
+	if len(fields) != 2 {
+		return Move{}, errors.New("A move is written as coordinate and word, e.g. 8H WORD.")
+	}
+
+	verticalIdx, horizontalIdx, direction, err := ParseCoordinate(fields[0])
+	if err != nil {
+		return Move{}, err
+	}
+
+	move := Move{
+		StartVerticalIdx:   verticalIdx,
+		StartHorizontalIdx: horizontalIdx,
+		Direction:          direction,
+	}
+
+	isOnBoard := false
+	letterIdx := 0
+	for _, character := range fields[1] {
+		switch {
+		case character == '(' && !isOnBoard:
+			isOnBoard = true
+		case character == ')' && isOnBoard:
+			isOnBoard = false
+		case unicode.IsLetter(character):
+			if isOnBoard {
+				move.BoardLetterIdxs = append(move.BoardLetterIdxs, letterIdx)
+			} else if unicode.IsLower(character) {
+				move.WildcardIdxs = append(move.WildcardIdxs, letterIdx)
+			}
+			move.Word += string(unicode.ToLower(character))
+			letterIdx++
+		default:
+			return Move{}, errors.New(fmt.Sprintf("Unexpected character %c in move %s", character, notation))
+		}
+	}
+
+	if isOnBoard {
+		return Move{}, errors.New("Missing closing parenthesis in move " + notation)
+	}
+	if move.Word == "" {
+		return Move{}, errors.New("Missing word in move " + notation)
+	}
+	return move, nil
+}
+
+func FormatMoveNotation(verticalIdx int, horizontalIdx int, direction string, tiles []Tile) string {
+	// Return the standard notation of a word
+	// Requires:
+	// - The position of the word's first tile, the direction of the word
+	//   and its tiles in order. Unlocked tiles have been placed in the move.
+
+	var word strings.Builder
+	isOnBoard := false
+	for _, tile := range tiles {
+		if tile.IsLocked != isOnBoard {
+			if tile.IsLocked {
+				word.WriteRune('(')
+			} else {
+				word.WriteRune(')')
+			}
+			isOnBoard = tile.IsLocked
+		}
+
+		character := tile.Letter.GetPlayedCharacter()
+		if !tile.Letter.IsWildcard() {
+			character = unicode.ToUpper(character)
+		}
+		word.WriteRune(character)
+	}
+	if isOnBoard {
+		word.WriteRune(')')
+	}
+
+	return FormatCoordinate(verticalIdx, horizontalIdx, direction) + " " + word.String()
+}
+
+func (wordOnBoard *WordOnBoard) GetNotation() string {
+	// Return the standard notation of a word found on the board.
+	// Words of a single letter are given as horizontal words.
+
+	direction := MOVE_DIRECTION_HORIZONTAL
+	if wordOnBoard.firstLetterXIdx == wordOnBoard.lastLetterXIdx &&
+		wordOnBoard.firstLetterYIdx != wordOnBoard.lastLetterYIdx {
+		direction = MOVE_DIRECTION_VERTICAL
+	}
+	return FormatMoveNotation(wordOnBoard.firstLetterYIdx, wordOnBoard.firstLetterXIdx,
+		direction, wordOnBoard.wordTiles)
+}
+
+func (game *Game) GetNotationOfUnlockedLetters() string {
+	// Return the standard notation of the move formed by the letters
+	// placed in the current turn, empty if there is none.
+	// The main word is the one along the line of all placed letters or,
+	// for a single letter, the horizontal word if there is one.
+
+	unlockedLetters := game.GetUnlockedLetters()
+	if len(unlockedLetters) == 0 {
+		return ""
+	}
+
+	firstLetter := unlockedLetters[0]
+	isHorizontal := len(unlockedLetters) > 1 &&
+		firstLetter.VerticalIdx == unlockedLetters[len(unlockedLetters)-1].VerticalIdx
+	if len(unlockedLetters) == 1 {
+		hasHorizontalWord, _, _ := GetHorizontalWordAtTile(firstLetter.VerticalIdx, firstLetter.HorizontalIdx, game.Tiles)
+		hasVerticalWord, _, _ := GetVerticalWordAtTile(firstLetter.VerticalIdx, firstLetter.HorizontalIdx, game.Tiles)
+		isHorizontal = hasHorizontalWord || !hasVerticalWord
+	}
+
+	if isHorizontal {
+		_, wordTiles, firstHorizontalIdx := GetHorizontalWordAtTile(firstLetter.VerticalIdx, firstLetter.HorizontalIdx, game.Tiles)
+		if len(wordTiles) == 0 {
+			wordTiles = []Tile{game.Tiles[firstLetter.VerticalIdx][firstLetter.HorizontalIdx]}
+			firstHorizontalIdx = firstLetter.HorizontalIdx
+		}
+		return FormatMoveNotation(firstLetter.VerticalIdx, firstHorizontalIdx, MOVE_DIRECTION_HORIZONTAL, wordTiles)
+	}
+
+	_, wordTiles, firstVerticalIdx := GetVerticalWordAtTile(firstLetter.VerticalIdx, firstLetter.HorizontalIdx, game.Tiles)
+	return FormatMoveNotation(firstVerticalIdx, firstLetter.HorizontalIdx, MOVE_DIRECTION_VERTICAL, wordTiles)
+}
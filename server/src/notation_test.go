@@ -0,0 +1,123 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCoordinateAcrossAndDown(t *testing.T) {
+
+	testCases := []struct {
+		coordinate            string
+		expectedVerticalIdx   int
+		expectedHorizontalIdx int
+		expectedDirection     string
+	}{
+		{"8H", 7, 7, MOVE_DIRECTION_HORIZONTAL},
+		{"H8", 7, 7, MOVE_DIRECTION_VERTICAL},
+		{"1A", 0, 0, MOVE_DIRECTION_HORIZONTAL},
+		{"o15", 14, 14, MOVE_DIRECTION_VERTICAL},
+		{"12C", 11, 2, MOVE_DIRECTION_HORIZONTAL},
+	}
+
+	for _, testCase := range testCases {
+		verticalIdx, horizontalIdx, direction, err := ParseCoordinate(testCase.coordinate)
+		if err != nil {
+			t.Errorf("Expected %s to be parsed, Was: %s", testCase.coordinate, err)
+			continue
+		}
+		if verticalIdx != testCase.expectedVerticalIdx || horizontalIdx != testCase.expectedHorizontalIdx ||
+			direction != testCase.expectedDirection {
+			t.Errorf("Expected %s to be %d/%d %s, Was: %d/%d %s", testCase.coordinate,
+				testCase.expectedVerticalIdx, testCase.expectedHorizontalIdx, testCase.expectedDirection,
+				verticalIdx, horizontalIdx, direction)
+		}
+		if formatted := FormatCoordinate(verticalIdx, horizontalIdx, direction); formatted != strings.ToUpper(testCase.coordinate) {
+			t.Errorf("Expected %s to be formatted as %s, Was: %s", testCase.coordinate, testCase.coordinate, formatted)
+		}
+	}
+}
+
+func TestParseCoordinateRejectsInvalidCoordinates(t *testing.T) {
+	for _, coordinate := range []string{"", "8", "H", "0H", "16A", "8P", "8HH", "H8H"} {
+		_, _, _, err := ParseCoordinate(coordinate)
+		if err == nil {
+			t.Errorf("Expected %q to be rejected", coordinate)
+		}
+	}
+}
+
+func TestParseMoveNotationMarksWildcardsAndBoardLetters(t *testing.T) {
+
+	move, err := ParseMoveNotation("8G C(AR)tS")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMove := Move{
+		StartVerticalIdx:   7,
+		StartHorizontalIdx: 6,
+		Direction:          MOVE_DIRECTION_HORIZONTAL,
+		Word:               "carts",
+		WildcardIdxs:       []int{3},
+		BoardLetterIdxs:    []int{1, 2},
+	}
+	if !reflect.DeepEqual(move, expectedMove) {
+		t.Errorf("Expected %+v, Was: %+v", expectedMove, move)
+	}
+}
+
+func TestParseMoveNotationRejectsMalformedMoves(t *testing.T) {
+	for _, notation := range []string{"8H", "8H CA(T", "8H C-T", "8H CAT DOG", "Z9 CAT"} {
+		_, err := ParseMoveNotation(notation)
+		if err == nil {
+			t.Errorf("Expected %q to be rejected", notation)
+		}
+	}
+}
+
+func TestFormatMoveNotationRoundTrip(t *testing.T) {
+
+	wildcardLetter := Letter{Character: WILDCARD_CHARACTER, DesignatedCharacter: 't', Attributes: letterDistribution[WILDCARD_CHARACTER]}
+	tiles := []Tile{
+		{Letter: Letter{Character: 'c', Attributes: letterDistribution['c']}},
+		{Letter: Letter{Character: 'a', Attributes: letterDistribution['a']}, IsLocked: true},
+		{Letter: Letter{Character: 'r', Attributes: letterDistribution['r']}, IsLocked: true},
+		{Letter: wildcardLetter},
+		{Letter: Letter{Character: 's', Attributes: letterDistribution['s']}},
+	}
+
+	notation := FormatMoveNotation(6, 7, MOVE_DIRECTION_VERTICAL, tiles)
+	if err := assertEquals("H7 C(AR)tS", notation); err != nil {
+		t.Error(err)
+	}
+
+	move, err := ParseMoveNotation(notation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if move.StartVerticalIdx != 6 || move.StartHorizontalIdx != 7 || move.Direction != MOVE_DIRECTION_VERTICAL {
+		t.Errorf("Expected the move to start at 6/7 going down, Was: %+v", move)
+	}
+}
+
+func TestPotentialPointsCarryNotation(t *testing.T) {
+
+	game := Game{Tiles: GetCleanTiles()}
+	game.Tiles[7][7].Letter = Letter{Character: 'c', Attributes: letterDistribution['c']}
+	game.Tiles[8][7].Letter = Letter{Character: 'a', Attributes: letterDistribution['a']}
+	game.Tiles[9][7].Letter = Letter{Character: 't', Attributes: letterDistribution['t']}
+	game.Tiles[9][7].IsLocked = true
+
+	potentialPointsForWords, err := GetPotentialPoints(&game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(potentialPointsForWords) != 1 {
+		t.Fatalf("Expected one new word, Was: %d", len(potentialPointsForWords))
+	}
+	if err := assertEquals("H8 CA(T)", potentialPointsForWords[0].Notation); err != nil {
+		t.Error(err)
+	}
+}
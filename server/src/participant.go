@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"gole/golelibs"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Index stored on participants that do not occupy a player seat
+const SPECTATOR_PLAYER_IDX = -1
+
+type Participant struct {
+	Id   string
+	Name string
+
+	// Index of the seat in the game's Players slice
+	// that this participant plays on, SPECTATOR_PLAYER_IDX for spectators
+	PlayerIdx int
+
+	// Index of the participant within the team's Members in team games
+	MemberIdx int
+
+	// Secret that authenticates the participant's requests.
+	// Only ever handed out once, when joining the game.
+	Token string `json:"-"`
+
+	// Id of the account the participant is logged in with,
+	// empty for guests
+	AccountId string
+
+	// Muted participants can still read but not post chat messages
+	IsMuted bool
+
+	// Send times of the participant's most recent chat messages
+	// used for rate limiting
+	RecentChatMessageTimes []time.Time `json:"-"`
+}
+
+func (participant *Participant) IsSpectator() bool {
+	return participant.PlayerIdx == SPECTATOR_PLAYER_IDX
+}
+
+func (game *Game) IsCreator(participant *Participant) bool {
+	// Tell whether the participant is logged in with the account
+	// that has created the game. Games created by guests have no creator.
+	return participant.AccountId != "" && participant.AccountId == game.CreatorAccountId
+}
+
+func (game *Game) JoinGame(name string, asSpectator bool, account *Account) (*Participant, error) {
+	// Register a new participant for the game
+	// Requires:
+	// - The name of the player seat to claim, or any name
+	//   if joining as a spectator
+	// - The account the participant is logged in with or nil for guests
+	// Guarantees:
+	// - If asSpectator is false, the player seat with the given name
+	//   is claimed by the new participant. In team games the seats
+	//   are those of the team members. Every seat can only be
+	//   claimed once. Seats that are linked to an account can only
+	//   be claimed by that account.
+	// - Participants with an account are named after their username
+	// - Return the new participant including its secret token
+	// - Return an error if the seat does not exist or
+	//   if it has already been claimed
+
+	name = strings.TrimSpace(name)
+	if account != nil {
+		name = account.Username
+	}
+	if name == "" {
+		return nil, errors.New("A name is required to join the game.")
+	}
+
+	participant := Participant{
+		Id:        golelibs.GetNewUUID(),
+		Name:      name,
+		PlayerIdx: SPECTATOR_PLAYER_IDX,
+		Token:     golelibs.GetNewSecretToken(),
+	}
+	if account != nil {
+		participant.AccountId = account.Id
+	}
+
+	if !asSpectator {
+		playerIdx, memberIdx, seatAccountId, exists := game.GetSeatByName(name)
+		if !exists {
+			return nil, errors.New("There is no player seat with the name " + name + " in this game.")
+		}
+		if seatAccountId != participant.AccountId {
+			return nil, errors.New("The player seat " + name + " can only be claimed by its account.")
+		}
+		for _, existingParticipant := range game.Participants {
+			if existingParticipant.PlayerIdx == playerIdx && existingParticipant.MemberIdx == memberIdx {
+				return nil, errors.New("The player seat " + name + " has already been claimed.")
+			}
+		}
+		participant.PlayerIdx = playerIdx
+		participant.MemberIdx = memberIdx
+	}
+
+	game.Participants = append(game.Participants, participant)
+	game.PublishChange(GAME_EVENT_PARTICIPANT_JOINED, participant)
+
+	return &game.Participants[len(game.Participants)-1], nil
+}
+
+func (game *Game) GetParticipantById(participantId string) (*Participant, error) {
+	for idx, participant := range game.Participants {
+		if participant.Id == participantId {
+			return &game.Participants[idx], nil
+		}
+	}
+	return nil, errors.New("Participant with ID " + participantId + " does not exist in the game.")
+}
+
+func (game *Game) GetParticipantByToken(token string) (*Participant, error) {
+	// Return the participant that the given secret token has been issued to
+	if token == "" {
+		return nil, errors.New("No token given.")
+	}
+	for idx, participant := range game.Participants {
+		if participant.Token == token {
+			return &game.Participants[idx], nil
+		}
+	}
+	return nil, errors.New("The given token is not valid for this game.")
+}
+
+func GetRequestToken(request *http.Request) string {
+	// Return the token from the request's
+	// "Authorization: Bearer <token>" header, or from the "token"
+	// query parameter for clients that can not set headers
+	// (e.g. a browser's EventSource), or an empty string if there is none
+	authorizationHeader := request.Header.Get("Authorization")
+	if !strings.HasPrefix(authorizationHeader, "Bearer ") {
+		return request.URL.Query().Get("token")
+	}
+	return strings.TrimSpace(strings.TrimPrefix(authorizationHeader, "Bearer "))
+}
+
+func (game *Game) GetParticipantForRequest(request *http.Request) (*Participant, error) {
+	// Authenticate the participant that has sent the given request
+	// Guarantees:
+	// - Accept the participant tokens issued when joining the game
+	// - Accept account session tokens of participants that joined
+	//   with their account
+	// - Never join the game, accounts that have a seat in the game
+	//   need to claim it by joining explicitly (see JoinGame)
+	// - Return an error if the request can not be authenticated
+
+	token := GetRequestToken(request)
+
+	participant, err := game.GetParticipantByToken(token)
+	if err == nil {
+		return participant, nil
+	}
+
+	account, err := GetAccountBySessionToken(token)
+	if err != nil {
+		return nil, errors.New("The request could not be authenticated for this game.")
+	}
+
+	for idx, existingParticipant := range game.Participants {
+		if existingParticipant.AccountId == account.Id {
+			return &game.Participants[idx], nil
+		}
+	}
+
+	return nil, errors.New("The account " + account.Username + " has not joined this game.")
+}
+
+func GetAccountForRequest(request *http.Request) (*Account, error) {
+	// Return the account whose session token has been sent with the
+	// request or nil if the request has been sent by a guest
+	// Guarantees:
+	// - Return an error if a token has been sent that is neither
+	//   a valid session token nor empty
+
+	token := GetRequestToken(request)
+	if token == "" {
+		return nil, nil
+	}
+	account, err := GetAccountBySessionToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
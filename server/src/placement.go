@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Codes of the reasons for which the letters placed in a turn are rejected
+const (
+	PLACEMENT_ERROR_NO_LETTERS         = "noLetters"
+	PLACEMENT_ERROR_NOT_IN_LINE        = "notInLine"
+	PLACEMENT_ERROR_GAP                = "gap"
+	PLACEMENT_ERROR_CENTER_NOT_COVERED = "centerNotCovered"
+	PLACEMENT_ERROR_TOO_SHORT          = "tooShort"
+	PLACEMENT_ERROR_NOT_CONNECTED      = "notConnected"
+)
+
+type PlacementError struct {
+	// One of the PLACEMENT_ERROR codes
+	Code    string
+	Message string
+}
+
+func (placementError *PlacementError) Error() string {
+	return placementError.Message
+}
+
+func newPlacementError(code string, format string, arguments ...interface{}) *PlacementError {
+	return &PlacementError{Code: code, Message: fmt.Sprintf(format, arguments...)}
+}
+
+func GetPlacementErrorCode(err error) string {
+	// Return the PLACEMENT_ERROR code of the error,
+	// empty if it is not a PlacementError
+	placementError, isPlacementError := err.(*PlacementError)
+	if !isPlacementError {
+		return ""
+	}
+	return placementError.Code
+}
+
+func hasLockedLetters(tiles [][]Tile) bool {
+	for _, tileRow := range tiles {
+		for _, tile := range tileRow {
+			if tile.Letter != (Letter{}) && tile.IsLocked {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isNextToLockedLetter(verticalIdx int, horizontalIdx int, tiles [][]Tile) bool {
+	neighbourOffsets := [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	for _, offset := range neighbourOffsets {
+		neighbourVerticalIdx := verticalIdx + offset[0]
+		neighbourHorizontalIdx := horizontalIdx + offset[1]
+		if !AreValidBoardCoordinates(neighbourVerticalIdx, neighbourHorizontalIdx) {
+			continue
+		}
+		neighbour := tiles[neighbourVerticalIdx][neighbourHorizontalIdx]
+		if neighbour.Letter != (Letter{}) && neighbour.IsLocked {
+			return true
+		}
+	}
+	return false
+}
+
+func (game *Game) CheckPlacementOfUnlockedLetters() error {
+	// Check that the letters placed in the current turn form a legal move
+	// as a whole, which placing them one by one can not ensure
+	// Guarantees:
+	// - Return nil if all placed letters lie in one row or column and,
+	//   together with the letters already on the board,
+	//   form one run without empty tiles in between
+	// - The first move of the game must cover the center tile and
+	//   consist of at least two letters, every later move must
+	//   touch a letter that is already on the board
+	// - Otherwise return a PlacementError with the PLACEMENT_ERROR code
+	//   of the first rule that is broken
+	// - The game is not changed
+
+	unlockedLetters := game.GetUnlockedLetters()
+	if len(unlockedLetters) == 0 {
+		return newPlacementError(PLACEMENT_ERROR_NO_LETTERS, "No letters have been placed on the board.")
+	}
+
+	// Unlocked letters are ordered row by row, so the first and the last
+	// span the run if all of them are in line
+	firstLetter := unlockedLetters[0]
+	lastLetter := unlockedLetters[len(unlockedLetters)-1]
+	isHorizontal := firstLetter.VerticalIdx == lastLetter.VerticalIdx
+	isVertical := firstLetter.HorizontalIdx == lastLetter.HorizontalIdx
+	for _, placedLetter := range unlockedLetters {
+		if (!isHorizontal || placedLetter.VerticalIdx != firstLetter.VerticalIdx) &&
+			(!isVertical || placedLetter.HorizontalIdx != firstLetter.HorizontalIdx) {
+			return newPlacementError(PLACEMENT_ERROR_NOT_IN_LINE,
+				"All letters must be placed in one row or column. Tile v:%d,h:%d is out of line.",
+				placedLetter.VerticalIdx, placedLetter.HorizontalIdx)
+		}
+	}
+
+	for verticalIdx := firstLetter.VerticalIdx; verticalIdx <= lastLetter.VerticalIdx; verticalIdx++ {
+		for horizontalIdx := firstLetter.HorizontalIdx; horizontalIdx <= lastLetter.HorizontalIdx; horizontalIdx++ {
+			if game.Tiles[verticalIdx][horizontalIdx].Letter == (Letter{}) {
+				return newPlacementError(PLACEMENT_ERROR_GAP,
+					"The placed letters must form one word without gaps. Tile v:%d,h:%d is empty.",
+					verticalIdx, horizontalIdx)
+			}
+		}
+	}
+
+	if !hasLockedLetters(game.Tiles) {
+		centerVerticalIdx := (VERTICAL_TILES_AMOUNT - 1) / 2
+		centerHorizontalIdx := (HORIZONTAL_TILES_AMOUNT - 1) / 2
+		if game.Tiles[centerVerticalIdx][centerHorizontalIdx].Letter == (Letter{}) {
+			return newPlacementError(PLACEMENT_ERROR_CENTER_NOT_COVERED,
+				"The first word must cover the center tile.")
+		}
+		if len(unlockedLetters) < 2 {
+			return newPlacementError(PLACEMENT_ERROR_TOO_SHORT,
+				"The first word must be at least two letters long.")
+		}
+		return nil
+	}
+
+	for _, placedLetter := range unlockedLetters {
+		if isNextToLockedLetter(placedLetter.VerticalIdx, placedLetter.HorizontalIdx, game.Tiles) {
+			return nil
+		}
+	}
+	return newPlacementError(PLACEMENT_ERROR_NOT_CONNECTED,
+		"The placed letters must touch a letter that is already on the board.")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCheckPlacementOfUnlockedLettersErrorCodes(t *testing.T) {
+
+	// Tiles are given as {verticalIdx, horizontalIdx}
+	testCases := []struct {
+		name         string
+		lockedTiles  [][2]int
+		placedTiles  [][2]int
+		expectedCode string
+	}{
+		{"nothing placed", nil, nil, PLACEMENT_ERROR_NO_LETTERS},
+		{"first word across the center", nil, [][2]int{{7, 6}, {7, 7}, {7, 8}}, ""},
+		{"first word down the center", nil, [][2]int{{6, 7}, {7, 7}}, ""},
+		{"single letter first move", nil, [][2]int{{7, 7}}, PLACEMENT_ERROR_TOO_SHORT},
+		{"first word off the center", nil, [][2]int{{3, 3}, {3, 4}}, PLACEMENT_ERROR_CENTER_NOT_COVERED},
+		{"diagonal letters", nil, [][2]int{{7, 7}, {8, 8}}, PLACEMENT_ERROR_NOT_IN_LINE},
+		{"letters bent around a corner", nil, [][2]int{{7, 7}, {8, 7}, {8, 8}}, PLACEMENT_ERROR_NOT_IN_LINE},
+		{"gap in first word", nil, [][2]int{{7, 7}, {7, 9}}, PLACEMENT_ERROR_GAP},
+		{"gap filled by existing letter", [][2]int{{7, 7}}, [][2]int{{7, 6}, {7, 8}}, ""},
+		{"gap next to existing letter", [][2]int{{7, 7}}, [][2]int{{5, 7}, {8, 7}}, PLACEMENT_ERROR_GAP},
+		{"hook onto existing letter", [][2]int{{7, 7}, {7, 8}}, [][2]int{{8, 8}}, ""},
+		{"word away from existing letters", [][2]int{{7, 7}}, [][2]int{{2, 2}, {2, 3}}, PLACEMENT_ERROR_NOT_CONNECTED},
+		{"letter only diagonally next to existing letter", [][2]int{{7, 7}}, [][2]int{{8, 8}}, PLACEMENT_ERROR_NOT_CONNECTED},
+	}
+
+	for _, testCase := range testCases {
+		game := Game{Tiles: GetCleanTiles()}
+		for _, lockedTile := range testCase.lockedTiles {
+			game.Tiles[lockedTile[0]][lockedTile[1]].Letter = MockGetLetter('a', MockGetLetterAttributes(9, 1))
+			game.Tiles[lockedTile[0]][lockedTile[1]].IsLocked = true
+		}
+		for _, placedTile := range testCase.placedTiles {
+			game.Tiles[placedTile[0]][placedTile[1]].Letter = MockGetLetter('a', MockGetLetterAttributes(9, 1))
+		}
+
+		err := game.CheckPlacementOfUnlockedLetters()
+		if code := GetPlacementErrorCode(err); code != testCase.expectedCode {
+			t.Errorf("Expected %s to be rejected with %q, Was: %q (%v)", testCase.name, testCase.expectedCode, code, err)
+		}
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"math/rand"
+	"sort"
 	"time"
 )
 
@@ -12,6 +12,43 @@ type Player struct {
 	Name          string
 	Points        int
 	LettersInHand []Letter
+
+	// Id of the account playing on this seat, empty for guests
+	AccountId string
+
+	// A player who has forfeited loses against everyone
+	// who has not, regardless of the points
+	HasForfeited bool
+
+	// In team games a Player is a team whose members share
+	// the hand and the points. Empty for all other games.
+	Members []TeamMember
+
+	// Index of the member that submits the team's next move
+	MemberIdxWithTurn int
+
+	// Set after a failed challenge under CHALLENGE_RULE_DOUBLE
+	LosesNextTurn bool
+
+	// Time the player has used and has left in games with a time control
+	Clock PlayerClock
+
+	// A player who has resigned has left the game for good
+	// and is skipped by the other players
+	HasResigned bool
+
+	// Last time the player has touched the board or their hand
+	// or has made any other move related request
+	LastActiveAt time.Time
+
+	// Order of the hand before the first letter of the current move
+	// has been placed, so that it can be restored if the move is taken back
+	LetterIdsInHandBeforeMove []string `json:"-"`
+
+	// One of the HAND_SORT_MODES the hand is arranged in
+	// after every refill, empty to keep the order of drawing.
+	// Taken from the account when the player is seated.
+	PreferredHandSortMode string
 }
 
 func (player *Player) GetLetterFromHandById(letterId string) (Letter, error) {
@@ -76,6 +113,35 @@ func (player *Player) SortHand(letterIds []string) error {
 
 }
 
+func (player *Player) getLetterIdsInHand() []string {
+	letterIds := make([]string, len(player.LettersInHand))
+	for letterIdx, letter := range player.LettersInHand {
+		letterIds[letterIdx] = letter.Id
+	}
+	return letterIds
+}
+
+func (player *Player) restoreHandOrder(letterIds []string) {
+	// Arrange the letters with the given Ids in the given order,
+	// followed by all other letters in the order they are in now
+
+	positionsById := make(map[string]int)
+	for position, letterId := range letterIds {
+		positionsById[letterId] = position
+	}
+
+	getPosition := func(letter Letter) int {
+		position, isKnown := positionsById[letter.Id]
+		if !isKnown {
+			return len(letterIds)
+		}
+		return position
+	}
+	sort.SliceStable(player.LettersInHand, func(i, j int) bool {
+		return getPosition(player.LettersInHand[i]) < getPosition(player.LettersInHand[j])
+	})
+}
+
 func (player *Player) ShuffleHand() {
 	// Randomly rearrange the array of letters in the player's hand
 	// Guarantees:
@@ -89,8 +155,7 @@ func (player *Player) ShuffleHand() {
 
 	// Get a slice of random indexes with the length of the player's
 	// LettersInHand slice
-	rand.Seed(time.Now().UTC().UnixNano())
-	randomIndexes := rand.Perm(len(player.LettersInHand))
+	randomIndexes := getRandomPermutation(len(player.LettersInHand), getRandomIntn)
 
 	for originalIdx, newIdx := range randomIndexes {
 		rearrangedLettersInHand[newIdx] = player.LettersInHand[originalIdx]
@@ -104,58 +169,41 @@ func (player *Player) ShuffleHand() {
 }
 
 func (player *Player) ReplaceWildcard(letterId string, letterCharacter rune) error {
-	// Replace the wildcard character on a letter with the given
-	// id with a normal letter.
+	// Choose ahead of placement which letter a wildcard letter
+	// in the player's hand will stand for.
 	// Requires:
 	// - The given letterId needs to be the id of a wildcard letter
-	//   in the hand of the currently active player and must unsubstituted
-	//   i.e. this function can only be called once for one letterId.
+	//   in the hand of the currently active player
 	// Guarantees:
-	// - Will replace the character on a wildcard letter in the letter
-	//   struct with the given id
-	// - Will return an error if the letterId does not refer to a letter
-	//   in the active players hand with a wildcard character on it.
+	// - Will designate the wildcard letter with the given id as the given
+	//   character. The letter itself stays a wildcard letter and can be
+	//   designated again until it is placed on the board.
+	// - Will return an error if the letterId does not refer to a wildcard
+	//   letter in the active players hand.
 	// - Will return an error if the given letter character is not
 	//   a valid character in the alphabet.
-	isWildcardLetter, err := player.IsRawWildcardLetter(letterId)
-
-	if err != nil {
-		return err
-	}
-
-	if !isWildcardLetter {
-		return errors.New("Cannot replace letter on non-wildcard letter.")
-	}
-
-	// Check if the given letterCharacter is a valid letter in the
-	// alphabet by trying to turn it into a Letter struct.
-	_, err = GetLetterStructFromRune(letterCharacter)
-	if err != nil {
-		return err
-	}
 
 	for idx, letterInHand := range player.LettersInHand {
 		if letterInHand.Id == letterId {
-			player.LettersInHand[idx].Character = letterCharacter
-			return nil
+			return player.LettersInHand[idx].DesignateWildcard(letterCharacter)
 		}
 	}
 
-	return errors.New("The Wildcard Replacement has failed for an unknown reason.")
+	return errors.New("Player has no letter with ID" + string(letterId) + " in hand.")
 
 }
 
 func (player *Player) IsRawWildcardLetter(letterId string) (bool, error) {
 	// Tell whether the letter with the given letterId is a wildcard tile,
-	// that has not yet been substituted with a real letter.
+	// that has not yet been designated as a real letter.
 	// Guarantees:
 	// - Return true or false to indicate whether the letter with given
-	//   is is an unsubstituted wildcard letter
+	//   is is an undesignated wildcard letter
 	// - Return an error as second return parameter it the given ID does
 	//   not refer to a letter in the players hand.
 	for _, letterInHand := range player.LettersInHand {
 		if letterInHand.Id == letterId {
-			return letterInHand.Character == WILDCARD_CHARACTER, nil
+			return letterInHand.IsWildcard() && letterInHand.DesignatedCharacter == 0, nil
 		}
 	}
 	return false, errors.New("Player has no letter with ID" + string(letterId) + " in hand.")
@@ -188,11 +236,15 @@ func (player *Player) AddLetterToHand(letter Letter) error {
 	// Guarantees:
 	// - Throw an error if the maximum of letters in hand
 	//   would exceed by adding this letter
+	// - Wildcard letters that come back from the board
+	//   lose their designation
 
 	if len(player.LettersInHand) >= MAX_NUMBER_OF_LETTERS_IN_HAND {
 		return errors.New("Cannot add letter to player hand. Maximum reached.")
 	}
 
+	letter.DesignatedCharacter = 0
+
 	player.LettersInHand = append(player.LettersInHand, letter)
 	return nil
 }
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// File in which the ratings of all players are stored
+var RATINGS_STORAGE_FILE = "gole_ratings.gob"
+
+// Glicko-2 parameters of a player who has never been rated before
+var INITIAL_RATING = 1500.0
+var INITIAL_RATING_DEVIATION = 350.0
+var INITIAL_RATING_VOLATILITY = 0.06
+
+// The Glicko-2 system constant constraining the change in volatility
+var GLICKO2_TAU = 0.5
+
+const glicko2Scale = 173.7178
+const glicko2ConvergenceTolerance = 0.000001
+
+type RatingHistoryEntry struct {
+	GameId          string
+	Time            time.Time
+	Rating          float64
+	RatingDeviation float64
+	Volatility      float64
+}
+
+type PlayerRating struct {
+	AccountId string
+
+	// Ratings are kept in separate pools per ruleset
+	Ruleset string
+
+	Rating          float64
+	RatingDeviation float64
+	Volatility      float64
+	GamesPlayed     int
+
+	// The rating after each rated game, oldest first
+	History []RatingHistoryEntry
+}
+
+type ratingStore struct {
+	// Ratings by ruleset and account Id
+	Pools map[string]map[string]*PlayerRating
+}
+
+var ratings = ratingStore{Pools: make(map[string]map[string]*PlayerRating)}
+var ratingsMutex sync.Mutex
+
+func LoadRatings() error {
+	// Restore all ratings from the RATINGS_STORAGE_FILE
+
+	ratingsMutex.Lock()
+	defer ratingsMutex.Unlock()
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+
+	var storedRatings ratingStore
+	hasStoredRatings, err := readGobFile(RATINGS_STORAGE_FILE, &storedRatings)
+	if err != nil || !hasStoredRatings {
+		return err
+	}
+	if storedRatings.Pools == nil {
+		storedRatings.Pools = make(map[string]map[string]*PlayerRating)
+	}
+
+	ratings = storedRatings
+	return nil
+}
+
+func saveRatings() error {
+	// Requires:
+	// - The ratingsMutex to be held by the caller
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+	return writeGobFile(RATINGS_STORAGE_FILE, ratings)
+}
+
+func getOrCreatePlayerRating(ruleset string, accountId string) *PlayerRating {
+	// Requires:
+	// - The ratingsMutex to be held by the caller
+
+	pool, exists := ratings.Pools[ruleset]
+	if !exists {
+		pool = make(map[string]*PlayerRating)
+		ratings.Pools[ruleset] = pool
+	}
+
+	playerRating, exists := pool[accountId]
+	if !exists {
+		playerRating = &PlayerRating{
+			AccountId:       accountId,
+			Ruleset:         ruleset,
+			Rating:          INITIAL_RATING,
+			RatingDeviation: INITIAL_RATING_DEVIATION,
+			Volatility:      INITIAL_RATING_VOLATILITY,
+		}
+		pool[accountId] = playerRating
+	}
+	return playerRating
+}
+
+func GetPlayerRatings(accountId string) []PlayerRating {
+	// Return the ratings of the account with the given Id
+	// in every pool the account has been rated in, sorted by ruleset
+
+	ratingsMutex.Lock()
+	defer ratingsMutex.Unlock()
+
+	var playerRatings []PlayerRating
+	for _, pool := range ratings.Pools {
+		if playerRating, exists := pool[accountId]; exists {
+			playerRatings = append(playerRatings, *playerRating)
+		}
+	}
+
+	sort.Slice(playerRatings, func(i, j int) bool {
+		return playerRatings[i].Ruleset < playerRatings[j].Ruleset
+	})
+
+	return playerRatings
+}
+
+type glicko2Opponent struct {
+	mu    float64
+	phi   float64
+	score float64
+}
+
+func glicko2G(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func glicko2E(mu float64, opponentMu float64, opponentPhi float64) float64 {
+	return 1 / (1 + math.Exp(-glicko2G(opponentPhi)*(mu-opponentMu)))
+}
+
+func CalculateGlicko2Rating(rating float64, ratingDeviation float64, volatility float64,
+	opponents []glicko2Opponent) (float64, float64, float64) {
+	// Calculate a player's new rating after one rating period
+	// following Mark Glickman's "Example of the Glicko-2 system".
+	// Requires:
+	// - The player's rating, rating deviation and volatility
+	//   on the Glicko (not Glicko-2) scale
+	// - The opponents of the rating period on the Glicko-2 scale
+	//   with the player's score against each (1 win, 0.5 draw, 0 loss)
+	// Guarantees:
+	// - Return the new rating, rating deviation and volatility
+	//   on the Glicko scale
+	// - If there are no opponents only the rating deviation grows
+
+	mu := (rating - INITIAL_RATING) / glicko2Scale
+	phi := ratingDeviation / glicko2Scale
+
+	if len(opponents) == 0 {
+		newPhi := math.Sqrt(phi*phi + volatility*volatility)
+		return rating, newPhi * glicko2Scale, volatility
+	}
+
+	var vInverse, deltaSum float64
+	for _, opponent := range opponents {
+		g := glicko2G(opponent.phi)
+		e := glicko2E(mu, opponent.mu, opponent.phi)
+		vInverse += g * g * e * (1 - e)
+		deltaSum += g * (opponent.score - e)
+	}
+	v := 1 / vInverse
+	delta := v * deltaSum
+
+	// Determine the new volatility with the Illinois algorithm
+	a := math.Log(volatility * volatility)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		return ex*(delta*delta-phi*phi-v-ex)/(2*math.Pow(phi*phi+v+ex, 2)) -
+			(x-a)/(GLICKO2_TAU*GLICKO2_TAU)
+	}
+
+	upper := a
+	var lower float64
+	if delta*delta > phi*phi+v {
+		lower = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*GLICKO2_TAU) < 0 {
+			k++
+		}
+		lower = a - k*GLICKO2_TAU
+	}
+
+	fUpper := f(upper)
+	fLower := f(lower)
+	for math.Abs(lower-upper) > glicko2ConvergenceTolerance {
+		c := upper + (upper-lower)*fUpper/(fLower-fUpper)
+		fC := f(c)
+		if fC*fLower <= 0 {
+			upper = lower
+			fUpper = fLower
+		} else {
+			fUpper = fUpper / 2
+		}
+		lower = c
+		fLower = fC
+	}
+	newVolatility := math.Exp(upper / 2)
+
+	phiStar := math.Sqrt(phi*phi + newVolatility*newVolatility)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*deltaSum
+
+	return newMu*glicko2Scale + INITIAL_RATING, newPhi * glicko2Scale, newVolatility
+}
+
+func UpdateRatingsForGame(game *Game) error {
+	// Update the ratings of all account players of a finished game.
+	// Requires:
+	// - A game that is over
+	// Guarantees:
+	// - Every pair of account players in the game is treated as
+	//   one result, so in games with 3-4 players everyone is
+	//   rated against each of the other players.
+	//   Guests are not rated and ignored as opponents.
+	// - All players are rated against the ratings the others
+	//   had before the game
+	// - Nothing is changed for casual games or for games
+	//   whose ratings have already been updated
+
+	if !game.GameOver {
+		return errors.New("Cannot rate a game that is not over.")
+	}
+
+	if game.Options.IsCasual || game.IsRated {
+		return nil
+	}
+
+	var ratedPlayerIdxs []int
+	for idx, player := range game.Players {
+		if player.AccountId != "" {
+			ratedPlayerIdxs = append(ratedPlayerIdxs, idx)
+		}
+	}
+
+	if len(ratedPlayerIdxs) < 2 {
+		return nil
+	}
+
+	ratingsMutex.Lock()
+	defer ratingsMutex.Unlock()
+
+	playerRatingsBeforeGame := make(map[int]PlayerRating)
+	for _, playerIdx := range ratedPlayerIdxs {
+		playerRatingsBeforeGame[playerIdx] = *getOrCreatePlayerRating(
+			game.Options.Ruleset, game.Players[playerIdx].AccountId)
+	}
+
+	now := time.Now().UTC()
+
+	for _, playerIdx := range ratedPlayerIdxs {
+		var opponents []glicko2Opponent
+		for _, opponentIdx := range ratedPlayerIdxs {
+			if opponentIdx == playerIdx {
+				continue
+			}
+			opponentRating := playerRatingsBeforeGame[opponentIdx]
+			opponents = append(opponents, glicko2Opponent{
+				mu:    (opponentRating.Rating - INITIAL_RATING) / glicko2Scale,
+				phi:   opponentRating.RatingDeviation / glicko2Scale,
+				score: game.GetResultAgainst(playerIdx, opponentIdx),
+			})
+		}
+
+		playerRating := getOrCreatePlayerRating(game.Options.Ruleset, game.Players[playerIdx].AccountId)
+		playerRating.Rating, playerRating.RatingDeviation, playerRating.Volatility =
+			CalculateGlicko2Rating(playerRating.Rating, playerRating.RatingDeviation,
+				playerRating.Volatility, opponents)
+		playerRating.GamesPlayed++
+		playerRating.History = append(playerRating.History, RatingHistoryEntry{
+			GameId:          game.Id,
+			Time:            now,
+			Rating:          playerRating.Rating,
+			RatingDeviation: playerRating.RatingDeviation,
+			Volatility:      playerRating.Volatility,
+		})
+
+		log.Printf("New %s rating of %s: %.1f", game.Options.Ruleset,
+			game.Players[playerIdx].Name, playerRating.Rating)
+	}
+
+	game.IsRated = true
+
+	return saveRatings()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func mockGetGlicko2Opponent(rating float64, ratingDeviation float64, score float64) glicko2Opponent {
+	return glicko2Opponent{
+		mu:    (rating - INITIAL_RATING) / glicko2Scale,
+		phi:   ratingDeviation / glicko2Scale,
+		score: score,
+	}
+}
+
+func TestCalculateGlicko2RatingGlickmansExample(t *testing.T) {
+
+	// The example calculation from Mark Glickman's description
+	// of the Glicko-2 system
+	rating, ratingDeviation, volatility := CalculateGlicko2Rating(1500, 200, 0.06,
+		[]glicko2Opponent{
+			mockGetGlicko2Opponent(1400, 30, 1),
+			mockGetGlicko2Opponent(1550, 100, 0),
+			mockGetGlicko2Opponent(1700, 300, 0),
+		})
+
+	if math.Abs(rating-1464.06) > 0.01 {
+		t.Errorf("Expected rating 1464.06, Was: %f", rating)
+	}
+	if math.Abs(ratingDeviation-151.52) > 0.01 {
+		t.Errorf("Expected rating deviation 151.52, Was: %f", ratingDeviation)
+	}
+	if math.Abs(volatility-0.05999) > 0.00001 {
+		t.Errorf("Expected volatility 0.05999, Was: %f", volatility)
+	}
+
+}
+
+func TestCalculateGlicko2RatingWithoutOpponents(t *testing.T) {
+
+	rating, ratingDeviation, _ := CalculateGlicko2Rating(1500, 200, 0.06, nil)
+
+	if rating != 1500 {
+		t.Errorf("Expected unchanged rating, Was: %f", rating)
+	}
+	if ratingDeviation <= 200 {
+		t.Errorf("Expected growing rating deviation, Was: %f", ratingDeviation)
+	}
+
+}
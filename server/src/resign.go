@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Why a player has left the game
+const (
+	RESIGN_REASON_RESIGNED  = "resigned"
+	RESIGN_REASON_INACTIVE  = "inactive"
+	RESIGN_REASON_TIMED_OUT = "timedOut"
+)
+
+type PlayerResignedEventData struct {
+	PlayerIdx int
+
+	// One of the RESIGN_REASONs
+	Reason            string
+	PlayerIdxWithTurn int
+	GameOver          bool
+}
+
+func (game *Game) GetNumberOfActivePlayers() int {
+	// Return the number of players who have not resigned
+	numberOfActivePlayers := 0
+	for _, player := range game.Players {
+		if !player.HasResigned {
+			numberOfActivePlayers++
+		}
+	}
+	return numberOfActivePlayers
+}
+
+func (game *Game) ResignPlayer(playerIdx int, reason string) error {
+	// Let the player on the given seat leave the game
+	// Requires:
+	// - The RESIGN_REASON, e.g. whether the player has been inactive
+	// Guarantees:
+	// - The letters in the player's hand, including those placed
+	//   in the current turn, are put back into the letter set
+	// - The player forfeits, i.e. loses against everyone else
+	// - If only one player is left, the game is over. Otherwise the
+	//   others play on and the resigned player is skipped from now on.
+	// - Return an error if the game is over or the player has resigned already
+
+	if game.GameOver {
+		return errors.New("Cannot resign. Game is over.")
+	}
+
+	if playerIdx < 0 || playerIdx >= len(game.Players) {
+		return errors.New(fmt.Sprintf("Player with index %d is not available.", playerIdx))
+	}
+
+	player := &game.Players[playerIdx]
+	if player.HasResigned {
+		return errors.New("The player has already resigned.")
+	}
+
+	if playerIdx == game.PlayerIdxWithTurn {
+		err := game.ReturnUnlockedLettersToHand()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, letter := range player.LettersInHand {
+		// Put the letter back at a random position so that
+		// nobody knows when it will be drawn again
+		game.returnLetterToSet(letter, game.getRandomIntn(len(game.LetterSet)+1))
+	}
+	player.LettersInHand = nil
+	player.HasResigned = true
+	player.HasForfeited = true
+
+	log.Printf("%s has resigned from game %s", player.Name, game.Id)
+
+	if game.GetNumberOfActivePlayers() < MIN_NUMBER_OF_PLAYERS {
+		game.ProvisionalMove = nil
+		game.EndGame()
+	} else if game.IsDuplicateGame() {
+		game.withdrawDuplicateSubmission(playerIdx)
+	} else if playerIdx == game.PlayerIdxWithTurn {
+		game.GiveTurnToNextPlayer()
+	}
+
+	game.PublishChange(GAME_EVENT_PLAYER_RESIGNED, PlayerResignedEventData{
+		PlayerIdx:         playerIdx,
+		Reason:            reason,
+		PlayerIdxWithTurn: game.PlayerIdxWithTurn,
+		GameOver:          game.GameOver,
+	})
+
+	return nil
+}
+
+func (game *Game) isCheckedForInactivity() bool {
+	// Duplicate games are not checked since their rounds end on their own.
+	// Neither are games stored before turns have been timed.
+	return !game.GameOver && !game.IsDuplicateGame() &&
+		game.Options.InactivityHours > 0 && !game.TurnStartedAt.IsZero()
+}
+
+func (game *Game) getInactivityThreshold() time.Duration {
+	// A player is never resigned before the time control
+	// would let their turn time out
+	threshold := time.Duration(game.Options.InactivityHours) * time.Hour
+	if timeLimit := game.getTimeLimitOfTurn(game.PlayerIdxWithTurn); timeLimit > threshold {
+		return timeLimit
+	}
+	return threshold
+}
+
+func (game *Game) getInactivityDeadline() time.Time {
+	// Return when the player with the turn is resigned for inactivity
+	lastActivity := game.TurnStartedAt
+	if lastActiveAt := game.Players[game.PlayerIdxWithTurn].LastActiveAt; lastActiveAt.After(lastActivity) {
+		lastActivity = lastActiveAt
+	}
+	return lastActivity.Add(game.getInactivityThreshold())
+}
+
+func (game *Game) markPlayerWithTurnActive() {
+	game.Players[game.PlayerIdxWithTurn].LastActiveAt = time.Now().UTC()
+}
+
+func (game *Game) markParticipantActive(participant *Participant) {
+	// Count a request the player has made as activity, e.g. sorting
+	// their hand while thinking about their move. Requests a client
+	// sends on its own, like keeping the event stream open, do not count.
+
+	if participant == nil || participant.IsSpectator() ||
+		participant.PlayerIdx < 0 || participant.PlayerIdx >= len(game.Players) {
+		return
+	}
+	game.Players[participant.PlayerIdx].LastActiveAt = time.Now().UTC()
+}
+
+func (game *Game) ScheduleInactivityDeadline() {
+	// Make sure a player who has left without resigning does not
+	// hold up the game forever
+
+	if !game.isCheckedForInactivity() {
+		return
+	}
+
+	turnStartedAt := game.TurnStartedAt
+	time.AfterFunc(time.Until(game.getInactivityDeadline()), func() {
+		game.Acquire()
+		defer game.Release()
+
+		if !game.TurnStartedAt.Equal(turnStartedAt) {
+			return
+		}
+		if !game.CheckInactivity() && !game.GameOver {
+			// The player has been active in the meantime
+			game.ScheduleInactivityDeadline()
+		}
+	})
+}
+
+func (game *Game) CheckInactivity() bool {
+	// Resign the player with the turn if they have been inactive
+	// for longer than the game's inactivity threshold
+	// Guarantees:
+	// - Return whether the player has been resigned
+
+	if !game.isCheckedForInactivity() {
+		return false
+	}
+
+	if time.Now().UTC().Before(game.getInactivityDeadline()) {
+		return false
+	}
+
+	err := game.ResignPlayer(game.PlayerIdxWithTurn, RESIGN_REASON_INACTIVE)
+	if err != nil {
+		log.Println("Could not resign inactive player: ", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func mockInactiveGame(t *testing.T, numberOfPlayers int) *Game {
+	// A casual game in which the first player has not done anything
+	// for two hours although only one hour of inactivity is allowed
+
+	useTemporaryStorage(t)
+
+	game := &Game{
+		Options:   GameOptions{IsCasual: true, InactivityHours: 1},
+		Tiles:     GetCleanTiles(),
+		LetterSet: mockLettersFromString("xyz"),
+	}
+	for playerIdx := 0; playerIdx < numberOfPlayers; playerIdx++ {
+		game.Players = append(game.Players, Player{LettersInHand: mockLettersFromString("cat")})
+	}
+	game.TurnStartedAt = time.Now().UTC().Add(-2 * time.Hour)
+	return game
+}
+
+func TestInactivePlayerIsSkippedInRotation(t *testing.T) {
+
+	game := mockInactiveGame(t, 3)
+
+	if !game.CheckInactivity() {
+		t.Fatal("Expected the inactive player to be resigned")
+	}
+	if game.GameOver || !game.Players[0].HasResigned || len(game.Players[0].LettersInHand) != 0 {
+		t.Errorf("Expected the others to play on without the resigned player's hand, Was: %+v", game.Players[0])
+	}
+	if len(game.LetterSet) != 6 {
+		t.Errorf("Expected the resigned player's letters to be put back, Was: %d letters", len(game.LetterSet))
+	}
+
+	var playerIdxsWithTurn []int
+	for i := 0; i < 3; i++ {
+		playerIdxsWithTurn = append(playerIdxsWithTurn, game.PlayerIdxWithTurn)
+		game.GiveTurnToNextPlayer()
+	}
+	for idx, expectedPlayerIdx := range []int{1, 2, 1} {
+		if playerIdxsWithTurn[idx] != expectedPlayerIdx {
+			t.Fatalf("Expected the turns to go to %v, Was: %v", []int{1, 2, 1}, playerIdxsWithTurn)
+		}
+	}
+}
+
+func TestInactivePlayerForfeitsTwoPlayerGame(t *testing.T) {
+
+	game := mockInactiveGame(t, 2)
+
+	if !game.CheckInactivity() {
+		t.Fatal("Expected the inactive player to be resigned")
+	}
+	if !game.GameOver || !game.Players[0].HasForfeited {
+		t.Errorf("Expected the game to be over with the inactive player forfeited, Was: %t, %+v",
+			game.GameOver, game.Players[0])
+	}
+	if winnerIdx := game.GetWinnerIdx(); winnerIdx != 1 {
+		t.Errorf("Expected the remaining player to win, Was: %d", winnerIdx)
+	}
+}
+
+func TestInactivityThreshold(t *testing.T) {
+
+	testCases := []struct {
+		description   string
+		options       GameOptions
+		lastActiveAgo time.Duration
+		isResigned    bool
+	}{
+		{"no threshold", GameOptions{}, 0, false},
+		{"threshold passed", GameOptions{InactivityHours: 1}, 0, true},
+		{"active since the turn started", GameOptions{InactivityHours: 1}, 30 * time.Minute, false},
+		{"shorter than the time per move", GameOptions{InactivityHours: 1,
+			TimeControl: TimeControl{Mode: TIME_CONTROL_CORRESPONDENCE, DaysPerMove: 1}}, 0, false},
+		{"longer than the time per move", GameOptions{InactivityHours: 1,
+			TimeControl: TimeControl{Mode: TIME_CONTROL_PER_MOVE, MoveSeconds: 60}}, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		game := mockInactiveGame(t, 2)
+		game.Options = testCase.options
+		game.Options.IsCasual = true
+		if testCase.lastActiveAgo > 0 {
+			game.Players[0].LastActiveAt = time.Now().UTC().Add(-testCase.lastActiveAgo)
+		}
+
+		if isResigned := game.CheckInactivity(); isResigned != testCase.isResigned {
+			t.Errorf("%s: Expected the player to be resigned: %t, Was: %t",
+				testCase.description, testCase.isResigned, isResigned)
+		}
+	}
+}
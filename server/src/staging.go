@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+)
+
+// Operations on the letters the player with the turn has placed
+// but not yet confirmed, each of them one single change of the game
+// instead of a series of removals and placements
+
+func (game *Game) getUnlockedLetterOnTile(verticalTileIdx int, horizontalTileIdx int) (Letter, error) {
+	// Return the letter that has been placed on the tile in the current turn
+	// Guarantees:
+	// - Return an error if the tile is off the board, empty or locked
+
+	letter, err := GetLetterFromTile(verticalTileIdx, horizontalTileIdx, game.Tiles)
+	if err != nil {
+		return Letter{}, errors.New("Tile empty")
+	}
+
+	if game.Tiles[verticalTileIdx][horizontalTileIdx].IsLocked {
+		return Letter{}, errors.New("Tile Locked")
+	}
+
+	return letter, nil
+}
+
+func RecallLetters(game *Game) error {
+	// Take all letters placed in the current turn back to the hand
+	// of the player with the turn at once
+	// Guarantees:
+	// - Return an error if the game is over or if no letters
+	//   have been placed, in which case nothing is changed
+
+	if game.GameOver {
+		return errors.New("Cannot recall letters. Game is over.")
+	}
+
+	if len(game.GetUnlockedLetters()) == 0 {
+		return errors.New("Cannot recall letters. No letters have been placed.")
+	}
+
+	err := game.ReturnUnlockedLettersToHand()
+	if err != nil {
+		return err
+	}
+	game.markPlayerWithTurnActive()
+
+	game.PublishChange(GAME_EVENT_BOARD_CHANGED, nil)
+
+	return nil
+}
+
+func MoveLetter(game *Game, fromVerticalTileIdx int, fromHorizontalTileIdx int,
+	toVerticalTileIdx int, toHorizontalTileIdx int) error {
+	// Move a letter placed in the current turn from one tile to another
+	// Guarantees:
+	// - The placement on the new tile is checked as if the letter
+	//   had been removed from its old tile first
+	// - Return an error if the game is over, if there is no unlocked
+	//   letter on the old tile or if the letter cannot be placed on the
+	//   new tile, in which case the letter stays where it was
+
+	if game.GameOver {
+		return errors.New("Cannot move letter. Game is over.")
+	}
+
+	letter, err := game.getUnlockedLetterOnTile(fromVerticalTileIdx, fromHorizontalTileIdx)
+	if err != nil {
+		return errors.New("Cannot move letter. " + err.Error())
+	}
+
+	game.Tiles[fromVerticalTileIdx][fromHorizontalTileIdx].Letter = Letter{}
+	game.UpdatePlacementLegalityOfAllTiles()
+
+	err = game.putLetterOnTile(toVerticalTileIdx, toHorizontalTileIdx, letter)
+	if err != nil {
+		game.Tiles[fromVerticalTileIdx][fromHorizontalTileIdx].Letter = letter
+		game.UpdatePlacementLegalityOfAllTiles()
+		return err
+	}
+	game.markPlayerWithTurnActive()
+
+	game.PublishChange(GAME_EVENT_BOARD_CHANGED, nil)
+
+	return nil
+}
+
+func SwapLetter(game *Game, verticalTileIdx int, horizontalTileIdx int,
+	letterId string, wildcardCharacter rune) error {
+	// Exchange a letter placed in the current turn with a letter
+	// from the hand of the player with the turn
+	// Requires:
+	// - The character a wildcard letter from the hand is placed as,
+	//   like for PlaceLetter
+	// Guarantees:
+	// - The letter from the hand takes the place of the letter on the board,
+	//   which goes back into the hand where the other letter was
+	// - Return an error if the game is over, if there is no unlocked letter
+	//   on the tile, if the player does not own the letter with the given Id
+	//   or if that letter is a wildcard letter that has not been designated.
+	//   Nothing is changed in this case.
+
+	if game.GameOver {
+		return errors.New("Cannot swap letter. Game is over.")
+	}
+
+	boardLetter, err := game.getUnlockedLetterOnTile(verticalTileIdx, horizontalTileIdx)
+	if err != nil {
+		return errors.New("Cannot swap letter. " + err.Error())
+	}
+
+	player := &game.Players[game.PlayerIdxWithTurn]
+	handLetter, err := player.GetLetterFromHandById(letterId)
+	if err != nil {
+		return err
+	}
+
+	if wildcardCharacter != 0 {
+		err = handLetter.DesignateWildcard(wildcardCharacter)
+		if err != nil {
+			return err
+		}
+	}
+	if handLetter.IsWildcard() && handLetter.DesignatedCharacter == 0 {
+		return errors.New("Cannot place unsubstituted wildcard letter.")
+	}
+
+	// The letter from the board takes the slot in the hand
+	// so that the order of the other letters is kept
+	for letterIdx, letterInHand := range player.LettersInHand {
+		if letterInHand.Id == letterId {
+			player.LettersInHand[letterIdx] = boardLetter
+			player.LettersInHand[letterIdx].DesignatedCharacter = 0
+		}
+	}
+	game.Tiles[verticalTileIdx][horizontalTileIdx].Letter = handLetter
+	game.UpdatePlacementLegalityOfAllTiles()
+	game.markPlayerWithTurnActive()
+
+	game.PublishChange(GAME_EVENT_BOARD_CHANGED, nil)
+
+	return nil
+}
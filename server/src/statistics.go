@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Amount of words listed in the most frequently played words
+var MAX_NUMBER_OF_FREQUENT_WORDS = 10
+
+type WordCount struct {
+	Word  string
+	Count int
+}
+
+// Words are given in upper case with wildcard letters in lower case
+
+type HighestScoringTurn struct {
+	GameId string
+	Points int
+	Words  []string
+}
+
+type HighestScoringWord struct {
+	GameId string
+	Word   string
+	Points int
+}
+
+type PlayerStatistics struct {
+	AccountId string
+
+	GamesPlayed int
+	GamesWon    int
+
+	// Final points over all games and number of turns
+	// that the averages are based on
+	TotalPoints int
+	TurnsPlayed int
+
+	AverageScore         float64
+	AveragePointsPerTurn float64
+	AverageVictoryMargin float64
+	HighestScoringTurn   HighestScoringTurn
+	HighestScoringWord   HighestScoringWord
+	LongestWord          string
+	Bingos               int
+	Passes               int
+	MostFrequentWords    []WordCount
+
+	// Intermediate values only needed while adding up the games
+	totalTurnPoints    int
+	totalVictoryMargin int
+	wordCountsByWord   map[string]int
+}
+
+func (statistics *PlayerStatistics) addGame(game *Game, playerIdx int) {
+	// Account for one finished game of the player
+	// sitting on the given seat
+
+	statistics.GamesPlayed++
+	statistics.TotalPoints += game.Players[playerIdx].Points
+
+	if game.GetWinnerIdx() == playerIdx {
+		statistics.GamesWon++
+		// Scores can be negative after penalties, so the best
+		// opponent is looked for starting with the first one
+		secondBestPoints, hasOpponent := 0, false
+		for opponentIdx, opponent := range game.Players {
+			if opponentIdx != playerIdx && (!hasOpponent || opponent.Points > secondBestPoints) {
+				secondBestPoints, hasOpponent = opponent.Points, true
+			}
+		}
+		statistics.totalVictoryMargin += game.Players[playerIdx].Points - secondBestPoints
+	}
+
+	for _, turnRecord := range game.Turns {
+		if turnRecord.PlayerIdx != playerIdx {
+			continue
+		}
+
+		statistics.TurnsPlayed++
+		statistics.totalTurnPoints += turnRecord.Points
+
+		if turnRecord.Type == TURN_TYPE_PASS {
+			statistics.Passes++
+		}
+
+		if turnRecord.IsBingo() {
+			statistics.Bingos++
+		}
+
+		if turnRecord.Type == TURN_TYPE_PLAY && turnRecord.Points > statistics.HighestScoringTurn.Points {
+			statistics.HighestScoringTurn = HighestScoringTurn{
+				GameId: game.Id,
+				Points: turnRecord.Points,
+			}
+			for _, playedWord := range turnRecord.Words {
+				statistics.HighestScoringTurn.Words = append(
+					statistics.HighestScoringTurn.Words, playedWord.GetMarkedWord())
+			}
+		}
+
+		// Words of moves that have been withdrawn after
+		// a challenge have never really been played
+		if turnRecord.Type != TURN_TYPE_PLAY {
+			continue
+		}
+
+		for _, playedWord := range turnRecord.Words {
+			word := strings.ToUpper(playedWord.Word)
+			statistics.wordCountsByWord[word]++
+
+			if playedWord.Points > statistics.HighestScoringWord.Points {
+				statistics.HighestScoringWord = HighestScoringWord{
+					GameId: game.Id,
+					Word:   playedWord.GetMarkedWord(),
+					Points: playedWord.Points,
+				}
+			}
+
+			if utf8.RuneCountInString(word) > utf8.RuneCountInString(statistics.LongestWord) {
+				statistics.LongestWord = word
+			}
+		}
+	}
+}
+
+func GetPlayerStatistics(accountId string) PlayerStatistics {
+	// Compute the lifetime statistics of the account with the given Id
+	// from all finished games the account has played in
+	// Guarantees:
+	// - Return the statistics with all averages set to 0 if
+	//   the account has not finished any games yet
+	// - The most frequently played words are sorted by count,
+	//   words with equal count alphabetically
+
+	statistics := PlayerStatistics{
+		AccountId:        accountId,
+		wordCountsByWord: make(map[string]int),
+	}
+
+	ForEachFinishedGame(accountId, func(game *Game) {
+		for playerIdx, player := range game.Players {
+			if player.AccountId == accountId {
+				statistics.addGame(game, playerIdx)
+			}
+		}
+	})
+
+	if statistics.GamesPlayed > 0 {
+		statistics.AverageScore = float64(statistics.TotalPoints) / float64(statistics.GamesPlayed)
+	}
+	if statistics.TurnsPlayed > 0 {
+		statistics.AveragePointsPerTurn = float64(statistics.totalTurnPoints) / float64(statistics.TurnsPlayed)
+	}
+	if statistics.GamesWon > 0 {
+		statistics.AverageVictoryMargin = float64(statistics.totalVictoryMargin) / float64(statistics.GamesWon)
+	}
+
+	for word, count := range statistics.wordCountsByWord {
+		statistics.MostFrequentWords = append(statistics.MostFrequentWords, WordCount{word, count})
+	}
+	sort.Slice(statistics.MostFrequentWords, func(i, j int) bool {
+		if statistics.MostFrequentWords[i].Count != statistics.MostFrequentWords[j].Count {
+			return statistics.MostFrequentWords[i].Count > statistics.MostFrequentWords[j].Count
+		}
+		return statistics.MostFrequentWords[i].Word < statistics.MostFrequentWords[j].Word
+	})
+	if len(statistics.MostFrequentWords) > MAX_NUMBER_OF_FREQUENT_WORDS {
+		statistics.MostFrequentWords = statistics.MostFrequentWords[:MAX_NUMBER_OF_FREQUENT_WORDS]
+	}
+
+	return statistics
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func (statistics *PlayerStatistics) WriteCSV(writer io.Writer) error {
+	// Write the statistics as CSV with the columns
+	// Statistic, Value and Detail (e.g. the word a value belongs to)
+
+	csvWriter := csv.NewWriter(writer)
+
+	records := [][]string{
+		{"Statistic", "Value", "Detail"},
+		{"GamesPlayed", strconv.Itoa(statistics.GamesPlayed), ""},
+		{"GamesWon", strconv.Itoa(statistics.GamesWon), ""},
+		{"AverageScore", formatFloat(statistics.AverageScore), ""},
+		{"AveragePointsPerTurn", formatFloat(statistics.AveragePointsPerTurn), ""},
+		{"AverageVictoryMargin", formatFloat(statistics.AverageVictoryMargin), ""},
+		{"HighestScoringTurn", strconv.Itoa(statistics.HighestScoringTurn.Points),
+			strings.Join(statistics.HighestScoringTurn.Words, " ")},
+		{"HighestScoringWord", strconv.Itoa(statistics.HighestScoringWord.Points),
+			statistics.HighestScoringWord.Word},
+		{"LongestWord", strconv.Itoa(utf8.RuneCountInString(statistics.LongestWord)),
+			statistics.LongestWord},
+		{"Bingos", strconv.Itoa(statistics.Bingos), ""},
+		{"Passes", strconv.Itoa(statistics.Passes), ""},
+	}
+
+	for _, wordCount := range statistics.MostFrequentWords {
+		records = append(records, []string{"FrequentWord", strconv.Itoa(wordCount.Count), wordCount.Word})
+	}
+
+	err := csvWriter.WriteAll(records)
+	if err != nil {
+		return err
+	}
+	return csvWriter.Error()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+)
+
+func mockFinishedGame(points ...int) *Game {
+	game := &Game{Id: "game", GameOver: true}
+	for _, playerPoints := range points {
+		game.Players = append(game.Players, Player{Points: playerPoints})
+	}
+	return game
+}
+
+func TestAddGameVictoryMargin(t *testing.T) {
+
+	testCases := []struct {
+		points         []int
+		playerIdx      int
+		gamesWon       int
+		victoryMargin  int
+		expectedPoints int
+	}{
+		{[]int{300, 250, 100}, 0, 1, 50, 300},
+		{[]int{10, -5, -20}, 0, 1, 15, 10},
+		{[]int{-3, -10}, 0, 1, 7, -3},
+		{[]int{100, 250}, 0, 0, 0, 100},
+	}
+
+	for _, testCase := range testCases {
+		statistics := PlayerStatistics{wordCountsByWord: make(map[string]int)}
+		statistics.addGame(mockFinishedGame(testCase.points...), testCase.playerIdx)
+
+		if statistics.GamesPlayed != 1 || statistics.GamesWon != testCase.gamesWon ||
+			statistics.totalVictoryMargin != testCase.victoryMargin || statistics.TotalPoints != testCase.expectedPoints {
+			t.Errorf("Expected %d won with a margin of %d and %d points for %v, Was: %d, %d and %d",
+				testCase.gamesWon, testCase.victoryMargin, testCase.expectedPoints, testCase.points,
+				statistics.GamesWon, statistics.totalVictoryMargin, statistics.TotalPoints)
+		}
+	}
+}
+
+func TestAddGameTurns(t *testing.T) {
+
+	game := mockFinishedGame(120, 80)
+	game.Turns = []TurnRecord{
+		{PlayerIdx: 0, Type: TURN_TYPE_PLAY, Points: 20, Words: []PlayedWord{{Word: "cat", Points: 20}}},
+		{PlayerIdx: 1, Type: TURN_TYPE_PLAY, Points: 90, Words: []PlayedWord{{Word: "quartzy", Points: 90}}},
+		{PlayerIdx: 0, Type: TURN_TYPE_WITHDRAWN, Points: 0, Words: []PlayedWord{{Word: "zyzzyvas", Points: 200}}},
+		{PlayerIdx: 0, Type: TURN_TYPE_PASS},
+		{PlayerIdx: 0, Type: TURN_TYPE_PLAY, Points: 100, PlacedLetters: make([]PlacedLetter, MAX_NUMBER_OF_LETTERS_IN_HAND),
+			Words: []PlayedWord{{Word: "recited", Points: 70, WildcardIdxs: []int{0}}, {Word: "cat", Points: 30}}},
+	}
+
+	statistics := PlayerStatistics{wordCountsByWord: make(map[string]int)}
+	statistics.addGame(game, 0)
+
+	if statistics.TurnsPlayed != 4 || statistics.totalTurnPoints != 120 {
+		t.Errorf("Expected 4 turns with 120 points, Was: %d with %d", statistics.TurnsPlayed, statistics.totalTurnPoints)
+	}
+	if statistics.Passes != 1 || statistics.Bingos != 1 {
+		t.Errorf("Expected 1 pass and 1 bingo, Was: %d and %d", statistics.Passes, statistics.Bingos)
+	}
+	if statistics.HighestScoringTurn.Points != 100 || len(statistics.HighestScoringTurn.Words) != 2 {
+		t.Errorf("Expected the bingo to be the highest scoring turn, Was: %+v", statistics.HighestScoringTurn)
+	}
+	if err := assertEquals("rECITED", statistics.HighestScoringWord.Word); err != nil {
+		t.Error(err)
+	}
+	if err := assertEquals("RECITED", statistics.LongestWord); err != nil {
+		t.Error(err)
+	}
+	if statistics.wordCountsByWord["CAT"] != 2 || statistics.wordCountsByWord["ZYZZYVAS"] != 0 ||
+		statistics.wordCountsByWord["QUARTZY"] != 0 {
+		t.Errorf("Expected only the player's words that have not been withdrawn to be counted, Was: %v",
+			statistics.wordCountsByWord)
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Directory in which the state of every game is stored in a file
+// of its own so that it survives restarts of the server
+var GAMES_STORAGE_DIRECTORY = "gole_games"
+
+const STORED_GAME_FILE_EXTENSION = ".gob"
+
+var storageMutex sync.Mutex
+
+func writeGobFile(filePath string, value interface{}) error {
+	// Encode the given value into the file at the given path.
+	// Guarantees:
+	// - The value is first written to a temporary file that then
+	//   replaces the original one so that a crash during writing
+	//   never leaves a half written file behind.
+	return writeEncodedFile(filePath, func(writer io.Writer) error {
+		return gob.NewEncoder(writer).Encode(value)
+	})
+}
+
+func writeCompressedGobFile(filePath string, value interface{}) error {
+	// Like writeGobFile but gzip compressed, for data that is
+	// rarely read again
+	return writeEncodedFile(filePath, func(writer io.Writer) error {
+		gzipWriter := gzip.NewWriter(writer)
+		err := gob.NewEncoder(gzipWriter).Encode(value)
+		if err != nil {
+			return err
+		}
+		return gzipWriter.Close()
+	})
+}
+
+func writeEncodedFile(filePath string, encode func(writer io.Writer) error) error {
+	// Write a file through the given encode function, see writeGobFile
+
+	temporaryFilePath := filePath + ".tmp"
+	file, err := os.Create(temporaryFilePath)
+	if err != nil {
+		return err
+	}
+
+	err = encode(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(temporaryFilePath, filePath)
+}
+
+func readGobFile(filePath string, value interface{}) (bool, error) {
+	// Decode the content of the file at the given path into value
+	// Guarantees:
+	// - Return false and no error if the file does not exist (yet)
+
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	err = gob.NewDecoder(file).Decode(value)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func readCompressedGobFile(filePath string, value interface{}) (bool, error) {
+	// Decode a file written by writeCompressedGobFile, see readGobFile
+
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return false, err
+	}
+	defer gzipReader.Close()
+
+	err = gob.NewDecoder(gzipReader).Decode(value)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func getStoredGameFilePath(gameId string) string {
+	return filepath.Join(GAMES_STORAGE_DIRECTORY, gameId+STORED_GAME_FILE_EXTENSION)
+}
+
+func (game *Game) Save() error {
+	// Persist the state of the game to its own file
+	// in the GAMES_STORAGE_DIRECTORY
+	// Requires:
+	// - The game to be acquired, see Acquire
+	// Guarantees:
+	// - Games that are read only are not stored
+
+	if game.isReadOnly {
+		return nil
+	}
+
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+
+	err := os.MkdirAll(GAMES_STORAGE_DIRECTORY, 0755)
+	if err != nil {
+		return err
+	}
+	return writeGobFile(getStoredGameFilePath(game.Id), game)
+}
+
+func deleteStoredGame(gameId string) error {
+	// Remove the file of a game that is no longer kept in memory
+
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+
+	err := os.Remove(getStoredGameFilePath(gameId))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func SaveGames() error {
+	// Persist the state of all games in memory, see Save
+	// Guarantees:
+	// - Every game is saved even if saving another one fails
+	// - Return the first error that has occurred
+
+	var firstErr error
+	for _, game := range getGamesInMemory() {
+		game.Acquire()
+		err := game.Save()
+		game.Release()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func LoadGames() error {
+	// Restore the state of all games from the GAMES_STORAGE_DIRECTORY
+	// Guarantees:
+	// - Replace the games in memory with the stored ones
+	// - Leave the games in memory untouched if nothing has been stored yet
+
+	storedGames, err := readStoredGames()
+	if err != nil {
+		return err
+	}
+
+	if len(storedGames) == 0 {
+		return nil
+	}
+
+	gamesMutex.Lock()
+	games = storedGames
+	gamesMutex.Unlock()
+	log.Printf("Loaded %d games from %s", len(storedGames), GAMES_STORAGE_DIRECTORY)
+
+	for _, game := range storedGames {
+		game.Acquire()
+		game.ScheduleDeadlines()
+		game.Release()
+	}
+	return nil
+}
+
+func readStoredGames() ([]*Game, error) {
+	// Decode every game in the GAMES_STORAGE_DIRECTORY
+
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+
+	gameFiles, err := os.ReadDir(GAMES_STORAGE_DIRECTORY)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var storedGames []*Game
+	for _, gameFile := range gameFiles {
+		if !strings.HasSuffix(gameFile.Name(), STORED_GAME_FILE_EXTENSION) {
+			continue
+		}
+		var game Game
+		_, err = readGobFile(filepath.Join(GAMES_STORAGE_DIRECTORY, gameFile.Name()), &game)
+		if err != nil {
+			return nil, err
+		}
+		storedGames = append(storedGames, &game)
+	}
+	return storedGames, nil
+}
+
+func (game *Game) Clone() (*Game, error) {
+	// Return a deep copy of the game that can be changed
+	// without affecting the original, e.g. to roll back
+	// a change that has turned out to be invalid
+
+	var buffer bytes.Buffer
+	err := gob.NewEncoder(&buffer).Encode(game)
+	if err != nil {
+		return nil, err
+	}
+
+	var clonedGame Game
+	err = gob.NewDecoder(&buffer).Decode(&clonedGame)
+	if err != nil {
+		return nil, err
+	}
+	// Unexported fields are not encoded
+	clonedGame.isReadOnly = game.isReadOnly
+	return &clonedGame, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLoadGamesRestoresEveryStoredGame(t *testing.T) {
+
+	useTemporaryStorage(t)
+	gamesBeforeTest := games
+	t.Cleanup(func() { games = gamesBeforeTest })
+
+	for _, game := range []*Game{{Id: "first", Version: 2}, {Id: "second"}} {
+		if err := game.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	games = nil
+	if err := LoadGames(); err != nil {
+		t.Fatal(err)
+	}
+
+	versionsById := make(map[string]int)
+	for _, game := range games {
+		versionsById[game.Id] = game.Version
+	}
+	if len(games) != 2 || versionsById["first"] != 2 {
+		t.Errorf("Expected both stored games, Was: %v", versionsById)
+	}
+
+	storedGame, err := GetGameByUUID("second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	removeGameFromMemory(storedGame)
+	if _, err := os.Stat(getStoredGameFilePath("second")); !os.IsNotExist(err) {
+		t.Errorf("Expected the file of a game removed from memory to be deleted, Was: %v", err)
+	}
+}
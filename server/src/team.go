@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var NUMBER_OF_TEAMS = 2
+var NUMBER_OF_TEAM_MEMBERS = 2
+
+type TeamMember struct {
+	Name string
+
+	// Id of the account of the member, empty for guests
+	AccountId string
+}
+
+func (game *Game) IsTeamGame() bool {
+	return game.Options.Variant == GAME_VARIANT_TEAMS
+}
+
+func AddTeams(seats []Seat, game *Game) error {
+	// Add the teams of a team game as players
+	// Requires:
+	// - Seats that have their TeamName set, NUMBER_OF_TEAM_MEMBERS
+	//   per team for NUMBER_OF_TEAMS teams
+	// Guarantees:
+	// - The teams take their turns in the order in which
+	//   their first member appears in the seats
+	// - Return an error if the seats do not form the required teams
+
+	var teamNames []string
+	membersByTeamName := make(map[string][]Seat)
+	for _, seat := range seats {
+		teamName := strings.TrimSpace(seat.TeamName)
+		if teamName == "" {
+			return errors.New("Every seat of a team game needs a team name.")
+		}
+		if _, exists := membersByTeamName[teamName]; !exists {
+			teamNames = append(teamNames, teamName)
+		}
+		membersByTeamName[teamName] = append(membersByTeamName[teamName], seat)
+	}
+
+	if len(teamNames) != NUMBER_OF_TEAMS {
+		return errors.New(fmt.Sprintf("A team game needs exactly %d teams.", NUMBER_OF_TEAMS))
+	}
+
+	for _, teamName := range teamNames {
+		err := AddTeam(teamName, membersByTeamName[teamName], game)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func AddTeam(teamName string, memberSeats []Seat, game *Game) error {
+	// Add a team to a team game. The team is added as one player
+	// with one hand and one score that all its members share.
+	// Guarantees:
+	// - Members on account seats are named after their account's username
+	// - Return an error if the number of members is not
+	//   NUMBER_OF_TEAM_MEMBERS, if a member's account does not exist
+	//   or if an account or a name is already taken in the game
+
+	if len(memberSeats) != NUMBER_OF_TEAM_MEMBERS {
+		return errors.New(fmt.Sprintf("The team %s needs exactly %d members.",
+			teamName, NUMBER_OF_TEAM_MEMBERS))
+	}
+
+	var members []TeamMember
+	for _, memberSeat := range memberSeats {
+		member := TeamMember{Name: strings.TrimSpace(memberSeat.GuestName)}
+
+		if memberSeat.AccountId != "" {
+			account, err := GetAccountById(memberSeat.AccountId)
+			if err != nil {
+				return err
+			}
+			if _, isSeated := game.GetPlayerIdxOfAccount(account.Id); isSeated {
+				return errors.New("The account " + account.Username + " already has a seat in this game.")
+			}
+			for _, otherMember := range members {
+				if otherMember.AccountId == account.Id {
+					return errors.New("The account " + account.Username + " already has a seat in this game.")
+				}
+			}
+			member = TeamMember{Name: account.Username, AccountId: account.Id}
+		}
+
+		if member.Name == "" {
+			return errors.New("A guest team member needs a name.")
+		}
+
+		_, _, _, isTaken := game.GetSeatByName(member.Name)
+		for _, otherMember := range members {
+			isTaken = isTaken || otherMember.Name == member.Name
+		}
+		if isTaken {
+			return errors.New("A player with the name " + member.Name + " already exists.")
+		}
+
+		members = append(members, member)
+	}
+
+	err := AddPlayer(Seat{GuestName: teamName}, game)
+	if err != nil {
+		return err
+	}
+	game.Players[len(game.Players)-1].Members = members
+
+	return nil
+}
+
+func (game *Game) GetSeatByName(name string) (int, int, string, bool) {
+	// Find the seat that the participant with the given name can claim
+	// Guarantees:
+	// - Return the index of the player and, in team games, the index
+	//   of the team member, along with the account linked to the seat
+	// - Return false as last value if there is no such seat
+
+	for playerIdx, player := range game.Players {
+		if len(player.Members) == 0 && player.Name == name {
+			return playerIdx, 0, player.AccountId, true
+		}
+		for memberIdx, member := range player.Members {
+			if member.Name == name {
+				return playerIdx, memberIdx, member.AccountId, true
+			}
+		}
+	}
+	return -1, -1, "", false
+}
+
+func (game *Game) GetPlayerIdxOfAccount(accountId string) (int, bool) {
+	// Return the index of the player, or in team games of the team,
+	// whose seat is linked to the account with the given Id
+	// or false as second value if there is none
+
+	for playerIdx, player := range game.Players {
+		if len(player.Members) == 0 && player.AccountId == accountId {
+			return playerIdx, true
+		}
+		for _, member := range player.Members {
+			if member.AccountId == accountId {
+				return playerIdx, true
+			}
+		}
+	}
+	return -1, false
+}
+
+func (game *Game) HasAccountSeats() bool {
+	// Tell whether any player, or in team games any team member,
+	// plays on a seat that is linked to an account
+	for _, player := range game.Players {
+		if player.AccountId != "" {
+			return true
+		}
+		for _, member := range player.Members {
+			if member.AccountId != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (game *Game) CheckPlayerWithTurn(participant *Participant) error {
+	// Check that the given participant plays on the seat with the turn,
+	// in team games any member of the team with the turn
+
+	if participant.PlayerIdx != game.PlayerIdxWithTurn {
+		return errors.New("It is " + game.Players[game.PlayerIdxWithTurn].Name + "'s turn.")
+	}
+	return nil
+}
+
+func (game *Game) CheckSubmitter(participant *Participant) error {
+	// Check that the given participant may finish the current turn
+	// Guarantees:
+	// - In team games only the member of the team with the turn
+	//   whose turn it is to submit may finish it, while both members
+	//   can place letters to consult on the move
+	// - In all other games the participant has to play on the seat
+	//   with the turn, see CheckPlayerWithTurn
+
+	if !game.IsTeamGame() {
+		return game.CheckPlayerWithTurn(participant)
+	}
+
+	team := game.Players[game.PlayerIdxWithTurn]
+	if participant.PlayerIdx != game.PlayerIdxWithTurn ||
+		participant.MemberIdx != team.MemberIdxWithTurn {
+		return errors.New("It is " + team.Members[team.MemberIdxWithTurn].Name +
+			"'s turn to submit the move of team " + team.Name + ".")
+	}
+	return nil
+}
@@ -8,6 +8,8 @@ package main
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
+	"testing"
 )
 
 func assertEquals(expected interface{}, real interface{}) error {
@@ -39,3 +41,16 @@ func MockGetTile(isLocked bool, letter Letter, effect SpecialTileEffect, isPlace
 		PlacementIsLegal: isPlacementLegal,
 	}
 }
+
+func useTemporaryStorage(t *testing.T) {
+	// Persist everything a test changes in a temporary directory
+	// that is removed after the test
+	directory := t.TempDir()
+	for _, storageFile := range []*string{&GAMES_STORAGE_DIRECTORY, &GAMES_ARCHIVE_DIRECTORY, &ACCOUNTS_STORAGE_FILE} {
+		storageFile, originalStorageFile := storageFile, *storageFile
+		*storageFile = filepath.Join(directory, filepath.Base(originalStorageFile))
+		t.Cleanup(func() {
+			*storageFile = originalStorageFile
+		})
+	}
+}
@@ -0,0 +1,691 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gole/golelibs"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// File in which all tournaments are stored
+var TOURNAMENTS_STORAGE_FILE = "gole_tournaments.gob"
+
+const (
+	TOURNAMENT_FORMAT_ROUND_ROBIN = "roundRobin"
+	TOURNAMENT_FORMAT_SWISS       = "swiss"
+)
+
+const (
+	TOURNAMENT_STATUS_REGISTRATION = "registration"
+	TOURNAMENT_STATUS_RUNNING      = "running"
+	TOURNAMENT_STATUS_FINISHED     = "finished"
+)
+
+// Spread that is credited for a bye and for a game won by forfeit
+// (and debited from the player who has forfeited)
+var BYE_SPREAD = 50
+var FORFEIT_SPREAD = 50
+
+var MIN_NUMBER_OF_TOURNAMENT_PLAYERS = 2
+
+type Pairing struct {
+	// The account Ids of the two players,
+	// only one for a bye
+	PlayerAccountIds []string
+	GameId           string
+	IsBye            bool
+	IsFinished       bool
+
+	// Account Id of the player who has forfeited the game, if any
+	ForfeitedBy string
+
+	// Points and results (1 win, 0.5 draw, 0 loss)
+	// in the same order as PlayerAccountIds once the game is finished
+	Points  []int
+	Results []float64
+}
+
+type TournamentRound struct {
+	Number   int
+	Pairings []Pairing
+}
+
+type Tournament struct {
+	Id     string
+	Name   string
+	Format string
+	Status string
+
+	// The account that has created the tournament
+	DirectorAccountId string
+
+	NumberOfRounds int
+
+	// Options for every game of the tournament
+	GameOptions GameOptions
+
+	PlayerAccountIds []string
+	Rounds           []TournamentRound
+	CreatedAt        time.Time
+}
+
+type Standing struct {
+	Rank        int
+	AccountId   string
+	Username    string
+	Wins        float64
+	Losses      float64
+	Spread      int
+	GamesPlayed int
+	Byes        int
+}
+
+var tournaments []*Tournament
+var tournamentsMutex sync.Mutex
+
+func LoadTournaments() error {
+	// Restore all tournaments from the TOURNAMENTS_STORAGE_FILE
+
+	tournamentsMutex.Lock()
+	defer tournamentsMutex.Unlock()
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+
+	var storedTournaments []*Tournament
+	hasStoredTournaments, err := readGobFile(TOURNAMENTS_STORAGE_FILE, &storedTournaments)
+	if err != nil || !hasStoredTournaments {
+		return err
+	}
+
+	tournaments = storedTournaments
+	return nil
+}
+
+func saveTournaments() error {
+	// Requires:
+	// - The tournamentsMutex to be held by the caller
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+	return writeGobFile(TOURNAMENTS_STORAGE_FILE, tournaments)
+}
+
+func getTournamentById(tournamentId string) (*Tournament, error) {
+	// Requires:
+	// - The tournamentsMutex to be held by the caller
+	for _, tournament := range tournaments {
+		if tournament.Id == strings.TrimSpace(tournamentId) {
+			return tournament, nil
+		}
+	}
+	return nil, errors.New("Tournament with ID " + tournamentId + " could not be found.")
+}
+
+func (tournament *Tournament) copy() Tournament {
+	// Return a copy that shares no slices with the tournament
+	// so that it can still be read once the tournamentsMutex is released
+	// Requires:
+	// - The tournamentsMutex to be held by the caller
+
+	tournamentCopy := *tournament
+	tournamentCopy.PlayerAccountIds = append([]string(nil), tournament.PlayerAccountIds...)
+	tournamentCopy.Rounds = append([]TournamentRound(nil), tournament.Rounds...)
+	for roundIdx, round := range tournamentCopy.Rounds {
+		round.Pairings = append([]Pairing(nil), round.Pairings...)
+		for pairingIdx, pairing := range round.Pairings {
+			pairing.PlayerAccountIds = append([]string(nil), pairing.PlayerAccountIds...)
+			pairing.Points = append([]int(nil), pairing.Points...)
+			pairing.Results = append([]float64(nil), pairing.Results...)
+			round.Pairings[pairingIdx] = pairing
+		}
+		tournamentCopy.Rounds[roundIdx] = round
+	}
+	return tournamentCopy
+}
+
+func GetTournamentById(tournamentId string) (Tournament, error) {
+	tournamentsMutex.Lock()
+	defer tournamentsMutex.Unlock()
+
+	tournament, err := getTournamentById(tournamentId)
+	if err != nil {
+		return Tournament{}, err
+	}
+	return tournament.copy(), nil
+}
+
+func GetTournaments() []Tournament {
+	// Return all tournaments, the most recently created first
+
+	tournamentsMutex.Lock()
+	defer tournamentsMutex.Unlock()
+
+	var allTournaments []Tournament
+	for idx := len(tournaments) - 1; idx >= 0; idx-- {
+		allTournaments = append(allTournaments, tournaments[idx].copy())
+	}
+	return allTournaments
+}
+
+func CreateTournament(director Account, name string, format string,
+	numberOfRounds int, gameOptions GameOptions) (Tournament, error) {
+	// Create a new tournament that players can register for
+	// Requires:
+	// - The account of the tournament director
+	// - A format, either TOURNAMENT_FORMAT_ROUND_ROBIN or
+	//   TOURNAMENT_FORMAT_SWISS
+	// - The number of rounds for swiss tournaments. For round robin
+	//   tournaments it is derived from the number of players.
+	// Guarantees:
+	// - Return the new tournament in registration state
+	// - Return an error if any of the parameters is invalid
+
+	if format != TOURNAMENT_FORMAT_ROUND_ROBIN && format != TOURNAMENT_FORMAT_SWISS {
+		return Tournament{}, errors.New("Unknown tournament format " + format)
+	}
+
+	if format == TOURNAMENT_FORMAT_SWISS && numberOfRounds < 1 {
+		return Tournament{}, errors.New("A swiss tournament needs at least one round.")
+	}
+
+	err := gameOptions.Validate()
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	if gameOptions.Variant != GAME_VARIANT_STANDARD {
+		return Tournament{}, errors.New("Tournaments can only be played in the standard variant.")
+	}
+
+	if gameOptions.IsSeeded {
+		return Tournament{}, errors.New("Tournament games cannot be seeded.")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Tournament{}, errors.New("A tournament needs a name.")
+	}
+
+	tournament := &Tournament{
+		Id:                golelibs.GetNewUUID(),
+		Name:              name,
+		Format:            format,
+		Status:            TOURNAMENT_STATUS_REGISTRATION,
+		DirectorAccountId: director.Id,
+		NumberOfRounds:    numberOfRounds,
+		GameOptions:       gameOptions,
+		CreatedAt:         time.Now().UTC(),
+	}
+
+	tournamentsMutex.Lock()
+	defer tournamentsMutex.Unlock()
+
+	tournaments = append(tournaments, tournament)
+
+	return tournament.copy(), saveTournaments()
+}
+
+func RegisterForTournament(tournamentId string, account Account) error {
+	// Register the given account as player of a tournament
+	// Guarantees:
+	// - Return an error if the registration has already been closed
+	//   or if the account is already registered
+
+	tournamentsMutex.Lock()
+	defer tournamentsMutex.Unlock()
+
+	tournament, err := getTournamentById(tournamentId)
+	if err != nil {
+		return err
+	}
+
+	if tournament.Status != TOURNAMENT_STATUS_REGISTRATION {
+		return errors.New("The registration for this tournament has been closed.")
+	}
+
+	for _, accountId := range tournament.PlayerAccountIds {
+		if accountId == account.Id {
+			return errors.New(account.Username + " is already registered for this tournament.")
+		}
+	}
+
+	tournament.PlayerAccountIds = append(tournament.PlayerAccountIds, account.Id)
+
+	return saveTournaments()
+}
+
+func StartTournament(tournamentId string, director Account) error {
+	// Close the registration and start the first round
+	// Guarantees:
+	// - Only the director of the tournament can start it
+	// - The games of the first round are created
+	// - Return an error if there are not enough players
+
+	tournamentsMutex.Lock()
+	defer tournamentsMutex.Unlock()
+
+	tournament, err := getTournamentById(tournamentId)
+	if err != nil {
+		return err
+	}
+
+	if tournament.DirectorAccountId != director.Id {
+		return errors.New("Only the director can start the tournament.")
+	}
+
+	if tournament.Status != TOURNAMENT_STATUS_REGISTRATION {
+		return errors.New("The tournament has already been started.")
+	}
+
+	if len(tournament.PlayerAccountIds) < MIN_NUMBER_OF_TOURNAMENT_PLAYERS {
+		return errors.New(fmt.Sprintf("A tournament needs at least %d players.",
+			MIN_NUMBER_OF_TOURNAMENT_PLAYERS))
+	}
+
+	if tournament.Format == TOURNAMENT_FORMAT_ROUND_ROBIN {
+		// Everyone plays everyone else once,
+		// with an odd number of players everyone sits out once
+		tournament.NumberOfRounds = len(tournament.PlayerAccountIds) - 1
+		if len(tournament.PlayerAccountIds)%2 == 1 {
+			tournament.NumberOfRounds++
+		}
+	}
+
+	tournament.Status = TOURNAMENT_STATUS_RUNNING
+
+	err = tournament.startNextRound()
+	if err != nil {
+		return err
+	}
+
+	return saveTournaments()
+}
+
+func (tournament *Tournament) startNextRound() error {
+	// Pair the players for the next round and create its games
+	// through the same path as every other new game.
+	// Byes are finished right away.
+
+	roundNumber := len(tournament.Rounds) + 1
+
+	var pairedAccountIds [][]string
+	if tournament.Format == TOURNAMENT_FORMAT_ROUND_ROBIN {
+		pairedAccountIds = GetRoundRobinPairings(tournament.PlayerAccountIds, roundNumber-1)
+	} else {
+		pairedAccountIds = tournament.GetSwissPairings(roundNumber == tournament.NumberOfRounds)
+	}
+
+	round := TournamentRound{Number: roundNumber}
+
+	for _, accountIds := range pairedAccountIds {
+		pairing := Pairing{PlayerAccountIds: accountIds}
+
+		if len(accountIds) == 1 {
+			pairing.IsBye = true
+			pairing.IsFinished = true
+			pairing.Points = []int{0}
+			pairing.Results = []float64{1}
+		} else {
+			gameId, err := startNewGame(tournament.GameOptions, tournament.DirectorAccountId, tournament.Id,
+				Seat{AccountId: accountIds[0]}, Seat{AccountId: accountIds[1]})
+			if err != nil {
+				return err
+			}
+			pairing.GameId = gameId
+		}
+
+		round.Pairings = append(round.Pairings, pairing)
+	}
+
+	tournament.Rounds = append(tournament.Rounds, round)
+	log.Printf("Started round %d of tournament %s", roundNumber, tournament.Name)
+
+	return nil
+}
+
+func GetRoundRobinPairings(accountIds []string, roundIdx int) [][]string {
+	// Return the pairings of one round of a round robin tournament
+	// following the circle method: the first player stays in place
+	// while all others rotate by one position every round.
+	// Guarantees:
+	// - Over len(accountIds)-1 rounds (or len(accountIds) rounds for
+	//   an odd number of players) every player meets every other player
+	//   exactly once
+	// - With an odd number of players the player that would be paired
+	//   with the missing player gets a bye, which is returned
+	//   as a pairing with only one account Id
+
+	participants := append([]string{}, accountIds...)
+	if len(participants)%2 == 1 {
+		participants = append(participants, "")
+	}
+
+	numberOfParticipants := len(participants)
+	rotated := make([]string, numberOfParticipants)
+	rotated[0] = participants[0]
+	for idx := 1; idx < numberOfParticipants; idx++ {
+		rotatedIdx := (idx-1+roundIdx)%(numberOfParticipants-1) + 1
+		rotated[rotatedIdx] = participants[idx]
+	}
+
+	var pairings [][]string
+	for idx := 0; idx < numberOfParticipants/2; idx++ {
+		first := rotated[idx]
+		second := rotated[numberOfParticipants-1-idx]
+		if first == "" {
+			pairings = append(pairings, []string{second})
+		} else if second == "" {
+			pairings = append(pairings, []string{first})
+		} else {
+			pairings = append(pairings, []string{first, second})
+		}
+	}
+	return pairings
+}
+
+func (tournament *Tournament) hasPlayed(accountId string, opponentAccountId string) bool {
+	for _, round := range tournament.Rounds {
+		for _, pairing := range round.Pairings {
+			if len(pairing.PlayerAccountIds) == 2 &&
+				((pairing.PlayerAccountIds[0] == accountId && pairing.PlayerAccountIds[1] == opponentAccountId) ||
+					(pairing.PlayerAccountIds[1] == accountId && pairing.PlayerAccountIds[0] == opponentAccountId)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (tournament *Tournament) hasHadBye(accountId string) bool {
+	for _, round := range tournament.Rounds {
+		for _, pairing := range round.Pairings {
+			if pairing.IsBye && pairing.PlayerAccountIds[0] == accountId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (tournament *Tournament) pairWithoutRematches(rankedAccountIds []string) ([][]string, bool) {
+	// Pair the given players from the top down so that everyone
+	// meets the highest ranked player they have not played yet.
+	// Backtracks if the remaining players can not be paired.
+
+	if len(rankedAccountIds) == 0 {
+		return nil, true
+	}
+
+	accountId := rankedAccountIds[0]
+	for opponentIdx := 1; opponentIdx < len(rankedAccountIds); opponentIdx++ {
+		opponentAccountId := rankedAccountIds[opponentIdx]
+		if tournament.hasPlayed(accountId, opponentAccountId) {
+			continue
+		}
+
+		var remainingAccountIds []string
+		remainingAccountIds = append(remainingAccountIds, rankedAccountIds[1:opponentIdx]...)
+		remainingAccountIds = append(remainingAccountIds, rankedAccountIds[opponentIdx+1:]...)
+
+		remainingPairings, ok := tournament.pairWithoutRematches(remainingAccountIds)
+		if ok {
+			return append([][]string{{accountId, opponentAccountId}}, remainingPairings...), true
+		}
+	}
+
+	return nil, false
+}
+
+func (tournament *Tournament) GetSwissPairings(isLastRound bool) [][]string {
+	// Return the pairings of the next round of a swiss tournament
+	// Guarantees:
+	// - With an odd number of players, the lowest ranked player
+	//   who has not had a bye yet gets the bye
+	// - Players are paired by their standing, avoiding rematches
+	//   where possible
+	// - The last round is paired King-of-the-Hill style, i.e. first
+	//   against second, third against fourth and so on, no matter
+	//   whether they have met before
+
+	var rankedAccountIds []string
+	for _, standing := range tournament.GetStandings() {
+		rankedAccountIds = append(rankedAccountIds, standing.AccountId)
+	}
+
+	var byePairings [][]string
+
+	if len(rankedAccountIds)%2 == 1 {
+		byeIdx := len(rankedAccountIds) - 1
+		for idx := len(rankedAccountIds) - 1; idx >= 0; idx-- {
+			if !tournament.hasHadBye(rankedAccountIds[idx]) {
+				byeIdx = idx
+				break
+			}
+		}
+		byePairings = append(byePairings, []string{rankedAccountIds[byeIdx]})
+		rankedAccountIds = append(rankedAccountIds[:byeIdx:byeIdx], rankedAccountIds[byeIdx+1:]...)
+	}
+
+	if !isLastRound {
+		pairings, ok := tournament.pairWithoutRematches(rankedAccountIds)
+		if ok {
+			return append(pairings, byePairings...)
+		}
+	}
+
+	var pairings [][]string
+	for idx := 0; idx+1 < len(rankedAccountIds); idx += 2 {
+		pairings = append(pairings, []string{rankedAccountIds[idx], rankedAccountIds[idx+1]})
+	}
+	return append(pairings, byePairings...)
+}
+
+func (tournament *Tournament) GetStandings() []Standing {
+	// Return the current standings of all registered players
+	// Guarantees:
+	// - Players are ranked by wins (draws counting half)
+	//   and then by cumulative spread
+	// - Byes count as a win with a spread of BYE_SPREAD
+	// - A forfeited game counts as a loss with a spread of -FORFEIT_SPREAD
+	//   for the player who has forfeited and as a win with FORFEIT_SPREAD
+	//   for the opponent
+
+	standingsByAccountId := make(map[string]*Standing)
+	for _, accountId := range tournament.PlayerAccountIds {
+		standing := &Standing{AccountId: accountId}
+		account, err := GetAccountById(accountId)
+		if err == nil {
+			standing.Username = account.Username
+		}
+		standingsByAccountId[accountId] = standing
+	}
+
+	for _, round := range tournament.Rounds {
+		for _, pairing := range round.Pairings {
+			if !pairing.IsFinished {
+				continue
+			}
+
+			if pairing.IsBye {
+				standing := standingsByAccountId[pairing.PlayerAccountIds[0]]
+				standing.Wins++
+				standing.Byes++
+				standing.Spread += BYE_SPREAD
+				continue
+			}
+
+			for idx, accountId := range pairing.PlayerAccountIds {
+				standing := standingsByAccountId[accountId]
+				opponentIdx := 1 - idx
+
+				standing.GamesPlayed++
+				standing.Wins += pairing.Results[idx]
+				standing.Losses += pairing.Results[opponentIdx]
+
+				if pairing.ForfeitedBy == accountId {
+					standing.Spread -= FORFEIT_SPREAD
+				} else if pairing.ForfeitedBy != "" {
+					standing.Spread += FORFEIT_SPREAD
+				} else {
+					standing.Spread += pairing.Points[idx] - pairing.Points[opponentIdx]
+				}
+			}
+		}
+	}
+
+	var standings []Standing
+	for _, accountId := range tournament.PlayerAccountIds {
+		standings = append(standings, *standingsByAccountId[accountId])
+	}
+
+	// Registration order breaks remaining ties so that
+	// the standings are stable
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Wins != standings[j].Wins {
+			return standings[i].Wins > standings[j].Wins
+		}
+		return standings[i].Spread > standings[j].Spread
+	})
+
+	for idx := range standings {
+		standings[idx].Rank = idx + 1
+	}
+
+	return standings
+}
+
+func GetTournamentStandings(tournamentId string) ([]Standing, error) {
+	tournamentsMutex.Lock()
+	defer tournamentsMutex.Unlock()
+
+	tournament, err := getTournamentById(tournamentId)
+	if err != nil {
+		return nil, err
+	}
+	return tournament.GetStandings(), nil
+}
+
+func RecordTournamentGameResult(game *Game) error {
+	// Account for the result of a finished tournament game
+	// Guarantees:
+	// - The pairing of the game is finished with the
+	//   players' points and results
+	// - Once all pairings of the current round are finished,
+	//   the next round is started or, after the last round,
+	//   the tournament is finished
+
+	tournamentsMutex.Lock()
+	defer tournamentsMutex.Unlock()
+
+	tournament, err := getTournamentById(game.TournamentId)
+	if err != nil {
+		return err
+	}
+
+	if len(tournament.Rounds) == 0 {
+		return errors.New("The tournament has not been started.")
+	}
+
+	currentRound := &tournament.Rounds[len(tournament.Rounds)-1]
+
+	for pairingIdx, pairing := range currentRound.Pairings {
+		if pairing.GameId != game.Id || pairing.IsFinished {
+			continue
+		}
+
+		pairing.IsFinished = true
+		pairing.Points = make([]int, len(pairing.PlayerAccountIds))
+		pairing.Results = make([]float64, len(pairing.PlayerAccountIds))
+		for idx, accountId := range pairing.PlayerAccountIds {
+			for playerIdx, player := range game.Players {
+				if player.AccountId != accountId {
+					continue
+				}
+				pairing.Points[idx] = player.Points
+				pairing.Results[idx] = game.GetResultAgainst(playerIdx, 1-playerIdx)
+				if player.HasForfeited {
+					pairing.ForfeitedBy = accountId
+				}
+			}
+		}
+		currentRound.Pairings[pairingIdx] = pairing
+	}
+
+	for _, pairing := range currentRound.Pairings {
+		if !pairing.IsFinished {
+			return saveTournaments()
+		}
+	}
+
+	if len(tournament.Rounds) < tournament.NumberOfRounds {
+		err = tournament.startNextRound()
+		if err != nil {
+			return err
+		}
+	} else {
+		tournament.Status = TOURNAMENT_STATUS_FINISHED
+		log.Printf("Tournament %s is finished", tournament.Name)
+	}
+
+	return saveTournaments()
+}
+
+func ForfeitTournamentGame(tournamentId string, director Account, accountId string) error {
+	// Let a player forfeit their game of the current round
+	// Guarantees:
+	// - Only the director of the tournament can record a forfeit
+	// - The game is ended and counts as a loss for the player
+	//   and as a win for the opponent
+
+	tournamentsMutex.Lock()
+	tournament, err := getTournamentById(tournamentId)
+	if err != nil {
+		tournamentsMutex.Unlock()
+		return err
+	}
+
+	if tournament.DirectorAccountId != director.Id {
+		tournamentsMutex.Unlock()
+		return errors.New("Only the director can record a forfeit.")
+	}
+
+	if tournament.Status != TOURNAMENT_STATUS_RUNNING {
+		tournamentsMutex.Unlock()
+		return errors.New("The tournament is not running.")
+	}
+
+	var gameId string
+	for _, pairing := range tournament.Rounds[len(tournament.Rounds)-1].Pairings {
+		for _, pairedAccountId := range pairing.PlayerAccountIds {
+			if pairedAccountId == accountId && !pairing.IsFinished && !pairing.IsBye {
+				gameId = pairing.GameId
+			}
+		}
+	}
+	// Ending the game records its result in the tournament
+	// which needs the lock itself
+	tournamentsMutex.Unlock()
+
+	if gameId == "" {
+		return errors.New("The player has no unfinished game in the current round.")
+	}
+
+	game, err := GetGameByUUID(gameId)
+	if err != nil {
+		return err
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	for playerIdx, player := range game.Players {
+		if player.AccountId == accountId {
+			return game.ForfeitPlayer(playerIdx)
+		}
+	}
+
+	return errors.New("The player is not part of the game.")
+}
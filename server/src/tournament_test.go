@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGetRoundRobinPairingsEveryoneMeetsOnce(t *testing.T) {
+
+	for _, numberOfPlayers := range []int{4, 5} {
+		var accountIds []string
+		for idx := 0; idx < numberOfPlayers; idx++ {
+			accountIds = append(accountIds, fmt.Sprintf("player%d", idx))
+		}
+
+		numberOfRounds := numberOfPlayers - 1 + numberOfPlayers%2
+		meetings := make(map[string]int)
+		byes := make(map[string]int)
+
+		for roundIdx := 0; roundIdx < numberOfRounds; roundIdx++ {
+			for _, pairing := range GetRoundRobinPairings(accountIds, roundIdx) {
+				if len(pairing) == 1 {
+					byes[pairing[0]]++
+					continue
+				}
+				meetings[pairing[0]+pairing[1]]++
+				meetings[pairing[1]+pairing[0]]++
+			}
+		}
+
+		for _, accountId := range accountIds {
+			for _, opponentAccountId := range accountIds {
+				if accountId != opponentAccountId && meetings[accountId+opponentAccountId] != 1 {
+					t.Errorf("Expected %s and %s to meet once, Was: %d times",
+						accountId, opponentAccountId, meetings[accountId+opponentAccountId])
+				}
+			}
+			if byes[accountId] != numberOfPlayers%2 {
+				t.Errorf("Expected %d byes for %s, Was: %d", numberOfPlayers%2, accountId, byes[accountId])
+			}
+		}
+	}
+
+}
+
+func TestGetSwissPairingsAvoidsRematches(t *testing.T) {
+
+	tournament := Tournament{
+		Format:           TOURNAMENT_FORMAT_SWISS,
+		PlayerAccountIds: []string{"a", "b", "c", "d"},
+		Rounds: []TournamentRound{{
+			Number: 1,
+			Pairings: []Pairing{
+				{PlayerAccountIds: []string{"a", "b"}, IsFinished: true,
+					Points: []int{400, 300}, Results: []float64{1, 0}},
+				{PlayerAccountIds: []string{"c", "d"}, IsFinished: true,
+					Points: []int{350, 340}, Results: []float64{1, 0}},
+			},
+		}},
+	}
+
+	pairings := tournament.GetSwissPairings(false)
+
+	err := assertEquals("a c d b", fmt.Sprintf("%s %s %s %s",
+		pairings[0][0], pairings[0][1], pairings[1][0], pairings[1][1]))
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+}
+
+func TestGetSwissPairingsKingOfTheHillInLastRound(t *testing.T) {
+
+	tournament := Tournament{
+		Format:           TOURNAMENT_FORMAT_SWISS,
+		PlayerAccountIds: []string{"a", "b", "c"},
+		Rounds: []TournamentRound{{
+			Number: 1,
+			Pairings: []Pairing{
+				{PlayerAccountIds: []string{"a", "b"}, IsFinished: true,
+					Points: []int{400, 300}, Results: []float64{1, 0}},
+				{PlayerAccountIds: []string{"c"}, IsBye: true, IsFinished: true,
+					Points: []int{0}, Results: []float64{1}},
+			},
+		}},
+	}
+
+	pairings := tournament.GetSwissPairings(true)
+
+	// a won against b and c had the bye, so a and c are first
+	// and second and meet, b is last and gets the bye
+	err := assertEquals("a c b", fmt.Sprintf("%s %s %s",
+		pairings[0][0], pairings[0][1], pairings[1][0]))
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+}
+
+func TestTournamentCopySharesNoSlices(t *testing.T) {
+
+	tournament := Tournament{
+		PlayerAccountIds: []string{"a", "b"},
+		Rounds: []TournamentRound{{
+			Number: 1,
+			Pairings: []Pairing{
+				{PlayerAccountIds: []string{"a", "b"}, Points: []int{0, 0}, Results: []float64{0, 0}},
+			},
+		}},
+	}
+
+	tournamentCopy := tournament.copy()
+	tournament.PlayerAccountIds[0] = "c"
+	tournament.Rounds[0].Number = 2
+	tournament.Rounds[0].Pairings[0].PlayerAccountIds[0] = "c"
+	tournament.Rounds[0].Pairings[0].Points[0] = 400
+	tournament.Rounds[0].Pairings[0].Results[0] = 1
+
+	pairingCopy := tournamentCopy.Rounds[0].Pairings[0]
+	if tournamentCopy.PlayerAccountIds[0] != "a" || tournamentCopy.Rounds[0].Number != 1 ||
+		pairingCopy.PlayerAccountIds[0] != "a" || pairingCopy.Points[0] != 0 || pairingCopy.Results[0] != 0 {
+		t.Errorf("Expected the copy to be unaffected by changes of the tournament, Was: %+v", tournamentCopy)
+	}
+}
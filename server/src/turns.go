@@ -0,0 +1,248 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Types of turns a player can take
+const (
+	TURN_TYPE_PLAY = "play"
+	TURN_TYPE_PASS = "pass"
+
+	// A move that has been withdrawn after a successful challenge
+	TURN_TYPE_WITHDRAWN = "withdrawn"
+)
+
+type PlacedLetter struct {
+	VerticalIdx   int
+	HorizontalIdx int
+	Letter        Letter
+
+	// Effect the tile had before the letter was locked on it
+	Effect SpecialTileEffect
+}
+
+type PlayedWord struct {
+	Word   string
+	Points int
+
+	// Positions of the letters in the word that are wildcard letters
+	WildcardIdxs []int
+}
+
+func (playedWord *PlayedWord) GetMarkedWord() string {
+	// Return the word in upper case with the wildcard letters
+	// in lower case, as in the standard move notation
+
+	wordCharacters := []rune(strings.ToUpper(playedWord.Word))
+	for _, wildcardIdx := range playedWord.WildcardIdxs {
+		if wildcardIdx < len(wordCharacters) {
+			wordCharacters[wildcardIdx] = unicode.ToLower(wordCharacters[wildcardIdx])
+		}
+	}
+	return string(wordCharacters)
+}
+
+type TurnRecord struct {
+	PlayerIdx int
+	Type      string
+	Time      time.Time
+
+	// Words formed in this turn with the points awarded for each
+	Words  []PlayedWord
+	Points int
+
+	// Letters the player has put on the board in this turn
+	PlacedLetters []PlacedLetter
+
+	// Letters the player has drawn from the letter set after the turn,
+	// in the order in which they have been drawn.
+	// Not exposed since they reveal the player's hand.
+	DrawnLetters []Letter `json:"-"`
+
+	// Order of the player's hand before the move, see RevertLastTurn
+	LetterIdsInHandBeforeTurn []string `json:"-"`
+
+	// Outcome of a challenge against the move, nil if not challenged
+	Challenge *ChallengeRecord
+
+	// The move in standard notation, e.g. "8G C(A)t", see notation.go
+	Notation string
+}
+
+func (turnRecord *TurnRecord) IsBingo() bool {
+	// A turn in which a player has used all letters of a full hand
+	return turnRecord.Type == TURN_TYPE_PLAY &&
+		len(turnRecord.PlacedLetters) == MAX_NUMBER_OF_LETTERS_IN_HAND
+}
+
+func (game *Game) GetUnlockedLetters() []PlacedLetter {
+	// Return all letters that have been placed on the board
+	// in the current turn, row by row from top left to bottom right
+
+	var unlockedLetters []PlacedLetter
+	for verticalIdx, tileRow := range game.Tiles {
+		for horizontalIdx, tile := range tileRow {
+			if tile.Letter != (Letter{}) && !tile.IsLocked {
+				unlockedLetters = append(unlockedLetters, PlacedLetter{
+					VerticalIdx:   verticalIdx,
+					HorizontalIdx: horizontalIdx,
+					Letter:        tile.Letter,
+					Effect:        tile.Effect,
+				})
+			}
+		}
+	}
+	return unlockedLetters
+}
+
+func ForEachFinishedGame(accountId string, handleGame func(game *Game)) {
+	// Call the given function once for every game that is over
+	// and that the account with the given Id has been seated in,
+	// including the archived ones.
+	// Only the archived games of the account are read from the archive.
+
+	for _, game := range getGamesInMemory() {
+		game.Acquire()
+		if game.GameOver {
+			summary := game.GetSummary()
+			if summary.hasAccount(accountId) {
+				handleGame(game)
+			}
+		}
+		game.Release()
+	}
+
+	for _, entry := range getArchivedGameIndexEntries() {
+		if !entry.summary.hasAccount(accountId) {
+			continue
+		}
+		game, err := GetArchivedGame(entry.summary.Id)
+		if err != nil {
+			log.Println("Could not read archived game: ", err)
+			continue
+		}
+		handleGame(game)
+	}
+}
+
+func (game *Game) ReturnUnlockedLettersToHand() error {
+	// Take all letters that have been placed in the current,
+	// unfinished turn off the board and hand them back
+	// to the player with the turn
+
+	for _, unlockedLetter := range game.GetUnlockedLetters() {
+		err := game.Players[game.PlayerIdxWithTurn].AddLetterToHand(unlockedLetter.Letter)
+		if err != nil {
+			return err
+		}
+		game.Tiles[unlockedLetter.VerticalIdx][unlockedLetter.HorizontalIdx].Letter = Letter{}
+	}
+	game.UpdatePlacementLegalityOfAllTiles()
+	return nil
+}
+
+func (game *Game) RevertLastTurn() (TurnRecord, error) {
+	// Take back the last finished turn as if it had never been played
+	// Guarantees:
+	// - Letters placed in the current, unfinished turn are
+	//   handed back to the player with the turn
+	// - The letters of the last turn are removed from the board,
+	//   their tiles get back their original effects and
+	//   the letters are handed back to the player who placed them.
+	//   Wildcard letters lose their designation.
+	// - The hand is arranged as it was before the move
+	// - The letters drawn after the last turn are put back on the
+	//   letter set so that they will be drawn again in the same order
+	// - The points of the turn are taken away from the player
+	//   and the player (in team games the same team member) has the turn again
+	// - Return the reverted turn
+	// - Return an error if the game is over or no turn has been played yet
+
+	if game.GameOver {
+		return TurnRecord{}, errors.New("Cannot revert turn. Game is over.")
+	}
+
+	if len(game.Turns) == 0 {
+		return TurnRecord{}, errors.New("Cannot revert turn. No turn has been played yet.")
+	}
+
+	err := game.ReturnUnlockedLettersToHand()
+	if err != nil {
+		return TurnRecord{}, err
+	}
+
+	lastTurn := game.Turns[len(game.Turns)-1]
+	player := &game.Players[lastTurn.PlayerIdx]
+
+	for drawnLetterIdx := len(lastTurn.DrawnLetters) - 1; drawnLetterIdx >= 0; drawnLetterIdx-- {
+		drawnLetter, err := player.PopLetterFromHand(lastTurn.DrawnLetters[drawnLetterIdx].Id)
+		if err != nil {
+			return TurnRecord{}, err
+		}
+		game.returnLetterToSet(drawnLetter, 0)
+	}
+
+	for _, placedLetter := range lastTurn.PlacedLetters {
+		tile := &game.Tiles[placedLetter.VerticalIdx][placedLetter.HorizontalIdx]
+		if tile.Letter.Id != placedLetter.Letter.Id {
+			return TurnRecord{}, errors.New(fmt.Sprintf(
+				"Cannot revert turn. Letter %c is not on the board anymore.", placedLetter.Letter.GetPlayedCharacter()))
+		}
+
+		err := player.AddLetterToHand(tile.Letter)
+		if err != nil {
+			return TurnRecord{}, err
+		}
+
+		tile.Letter = Letter{}
+		tile.IsLocked = false
+		tile.Effect = placedLetter.Effect
+	}
+	player.restoreHandOrder(lastTurn.LetterIdsInHandBeforeTurn)
+
+	player.Points -= lastTurn.Points
+	game.Turns = game.Turns[:len(game.Turns)-1]
+	game.chargeTurnTime()
+	game.PlayerIdxWithTurn = lastTurn.PlayerIdx
+	if len(player.Members) > 0 {
+		player.MemberIdxWithTurn = (player.MemberIdxWithTurn + len(player.Members) - 1) % len(player.Members)
+	}
+	game.UpdatePlacementLegalityOfAllTiles()
+	game.startTurnClock()
+
+	return lastTurn, nil
+}
+
+func (game *Game) PassTurn() error {
+	// Let the player with the turn pass without playing
+	// Guarantees:
+	// - Letters placed in the current turn are handed back to the player
+	// - A provisional move of the previous player becomes final
+	// - A pass is recorded and the turn is given to the next player
+	// - Return an error if the game is over
+
+	game.acceptProvisionalMove()
+	if game.GameOver {
+		return errors.New("Cannot pass. Game is over.")
+	}
+
+	err := game.ReturnUnlockedLettersToHand()
+	if err != nil {
+		return err
+	}
+
+	game.Turns = append(game.Turns, TurnRecord{
+		PlayerIdx: game.PlayerIdxWithTurn,
+		Type:      TURN_TYPE_PASS,
+		Time:      time.Now().UTC(),
+	})
+	game.GiveTurnToNextPlayer()
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+type UndoRequest struct {
+	// The player who has played the move and wants to take it back
+	PlayerIdx int
+
+	// Index of the move in the game's Turns
+	TurnIdx int
+	Time    time.Time
+
+	// Players who have agreed to take back the move
+	ApprovingPlayerIdxs []int
+}
+
+func (game *Game) getPendingUndoRequest() *UndoRequest {
+	// Return the open undo request, nil if there is none.
+	// A request is dropped once another turn has been finished.
+
+	if game.UndoRequest != nil && (game.GameOver || game.UndoRequest.TurnIdx != len(game.Turns)-1) {
+		game.UndoRequest = nil
+	}
+	return game.UndoRequest
+}
+
+func (game *Game) RequestUndo(playerIdx int) error {
+	// Ask the opponents to agree to take back the player's last move
+	// Guarantees:
+	// - Return an error if the game does not allow undoing moves,
+	//   if the last move has not been played by the player or
+	//   if an undo has been requested already
+
+	if !game.Options.AllowsUndo || !game.Options.IsCasual {
+		return errors.New("Moves can only be taken back in casual games that allow it.")
+	}
+
+	if game.GameOver {
+		return errors.New("Cannot undo move. Game is over.")
+	}
+
+	if game.getPendingUndoRequest() != nil {
+		return errors.New("An undo has been requested already.")
+	}
+
+	if len(game.Turns) == 0 {
+		return errors.New("Cannot undo move. No move has been played yet.")
+	}
+
+	lastTurn := game.Turns[len(game.Turns)-1]
+	if lastTurn.PlayerIdx != playerIdx || lastTurn.Type != TURN_TYPE_PLAY {
+		return errors.New("Only the player who has played the last move can ask to take it back.")
+	}
+
+	game.UndoRequest = &UndoRequest{
+		PlayerIdx: playerIdx,
+		TurnIdx:   len(game.Turns) - 1,
+		Time:      time.Now().UTC(),
+	}
+
+	game.PublishChange(GAME_EVENT_UNDO_REQUESTED, *game.UndoRequest)
+	return nil
+}
+
+func (game *Game) ApproveUndo(playerIdx int) error {
+	// Agree to take back the move of the open undo request
+	// Guarantees:
+	// - Once every opponent who has not resigned has agreed, the move
+	//   is taken back as if it had never been played (see RevertLastTurn)
+	// - The time since the request is not charged to the player with the turn
+	// - Return an error if there is no undo request the player can approve
+
+	undoRequest := game.getPendingUndoRequest()
+	if undoRequest == nil {
+		return errors.New("There is no undo request.")
+	}
+
+	if playerIdx == undoRequest.PlayerIdx {
+		return errors.New("Players cannot approve their own undo request.")
+	}
+
+	if playerIdx < 0 || playerIdx >= len(game.Players) || game.Players[playerIdx].HasResigned {
+		return errors.New("Only players in the game can approve an undo request.")
+	}
+
+	for _, approvingPlayerIdx := range undoRequest.ApprovingPlayerIdxs {
+		if approvingPlayerIdx == playerIdx {
+			return errors.New("The player has already approved the undo request.")
+		}
+	}
+	undoRequest.ApprovingPlayerIdxs = append(undoRequest.ApprovingPlayerIdxs, playerIdx)
+
+	// The player who has asked is active too but does not need to approve
+	if len(undoRequest.ApprovingPlayerIdxs) < game.GetNumberOfActivePlayers()-1 {
+		game.PublishChange(GAME_EVENT_UNDO_REQUESTED, *undoRequest)
+		return nil
+	}
+
+	gameBeforeUndo, err := game.Clone()
+	if err != nil {
+		return err
+	}
+
+	// The player with the turn is not charged for
+	// the time the opponents have taken to agree
+	game.chargeTurnTimeUntil(undoRequest.Time)
+
+	game.UndoRequest = nil
+	game.ProvisionalMove = nil
+	revertedTurn, err := game.RevertLastTurn()
+	if err != nil {
+		*game = *gameBeforeUndo
+		return err
+	}
+
+	game.PublishChange(GAME_EVENT_MOVE_UNDONE, TurnFinishedEventData{
+		GainedPoints:      -revertedTurn.Points,
+		PlayerIdxWithTurn: game.PlayerIdxWithTurn,
+	})
+	return nil
+}
+
+func (game *Game) DeclineUndo(playerIdx int) error {
+	// Refuse to take back the move of the open undo request,
+	// which closes the request
+
+	undoRequest := game.getPendingUndoRequest()
+	if undoRequest == nil {
+		return errors.New("There is no undo request.")
+	}
+
+	if playerIdx == undoRequest.PlayerIdx {
+		return errors.New("Players cannot decline their own undo request.")
+	}
+
+	game.UndoRequest = nil
+	game.PublishChange(GAME_EVENT_UNDO_DECLINED, nil)
+	return nil
+}
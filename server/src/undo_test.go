@@ -0,0 +1,132 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func mockUndoGame(t *testing.T, options GameOptions) *Game {
+	// A game of two players with the hand "catsdog" each in which
+	// the first player has played "to" in the center of the board.
+	// Moves are not checked against the dictionary under a challenge rule.
+
+	useTemporaryStorage(t)
+
+	game := &Game{Options: options, Tiles: GetCleanTiles(), Players: []Player{{Name: "a"}, {Name: "b"}}}
+	for playerIdx := range game.Players {
+		for _, letter := range mockLettersFromString("catsdog") {
+			letter.Id = game.Players[playerIdx].Name + letter.Id
+			game.Players[playerIdx].LettersInHand = append(game.Players[playerIdx].LettersInHand, letter)
+		}
+	}
+	game.LetterSet = mockLettersFromString("xyz")
+	game.commitToLetterSet()
+	game.UpdatePlacementLegalityOfAllTiles()
+	game.startTurnClock()
+
+	for _, placement := range []struct {
+		horizontalIdx int
+		letterId      string
+	}{{7, "a2"}, {8, "a5"}} {
+		err := game.placeLetterFromHand(7, placement.horizontalIdx, placement.letterId, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, _, err := FinishTurn(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return game
+}
+
+func TestRequestUndoOnlyInCasualGames(t *testing.T) {
+
+	testCases := []struct {
+		options   GameOptions
+		isAllowed bool
+	}{
+		{GameOptions{ChallengeRule: CHALLENGE_RULE_SINGLE, IsCasual: true, AllowsUndo: true}, true},
+		{GameOptions{ChallengeRule: CHALLENGE_RULE_SINGLE, AllowsUndo: true}, false},
+		{GameOptions{ChallengeRule: CHALLENGE_RULE_SINGLE, IsCasual: true}, false},
+	}
+
+	for _, testCase := range testCases {
+		game := mockUndoGame(t, testCase.options)
+		err := game.RequestUndo(0)
+		if (err == nil) != testCase.isAllowed {
+			t.Errorf("Expected undo to be allowed with options %+v: %t, Was: %v",
+				testCase.options, testCase.isAllowed, err)
+		}
+	}
+}
+
+func TestApproveUndoRestoresGame(t *testing.T) {
+
+	game := mockUndoGame(t, GameOptions{
+		ChallengeRule: CHALLENGE_RULE_SINGLE,
+		IsCasual:      true,
+		AllowsUndo:    true,
+		TimeControl:   TimeControl{Mode: TIME_CONTROL_BANK, BankSeconds: 3600},
+	})
+	if err := game.RequestUndo(1); err == nil {
+		t.Error("Expected only the player of the last move to be able to request an undo")
+	}
+	if err := game.RequestUndo(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := game.ApproveUndo(0); err == nil {
+		t.Error("Expected players not to be able to approve their own undo request")
+	}
+
+	// The second player has thought for two minutes before
+	// the request and has waited eight minutes for the undo
+	game.TurnStartedAt = time.Now().UTC().Add(-10 * time.Minute)
+	game.UndoRequest.Time = time.Now().UTC().Add(-8 * time.Minute)
+
+	if err := game.ApproveUndo(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if game.UndoRequest != nil || len(game.Turns) != 0 || game.PlayerIdxWithTurn != 0 {
+		t.Errorf("Expected the move to be taken back, Was: %+v with %d turns and player %d with the turn",
+			game.UndoRequest, len(game.Turns), game.PlayerIdxWithTurn)
+	}
+	if game.Tiles[7][7].Letter.Id != "" || game.Tiles[7][8].Letter.Id != "" || game.Players[0].Points != 0 {
+		t.Errorf("Expected the letters and points of the move to be taken back")
+	}
+	if err := assertEquals("catsdog", lettersToString(game.Players[0].LettersInHand)); err != nil {
+		t.Error(err)
+	}
+	if err := assertEquals("xyz", lettersToString(game.LetterSet)); err != nil {
+		t.Error(err)
+	}
+	if usedTime := game.Players[1].Clock.UsedTime; usedTime < 2*time.Minute || usedTime > 3*time.Minute {
+		t.Errorf("Expected the second player to be charged for two minutes, Was: %s", usedTime)
+	}
+}
+
+func TestDeclineUndoKeepsMove(t *testing.T) {
+
+	game := mockUndoGame(t, GameOptions{ChallengeRule: CHALLENGE_RULE_SINGLE, IsCasual: true, AllowsUndo: true})
+	if err := game.RequestUndo(0); err != nil {
+		t.Fatal(err)
+	}
+	if err := game.RequestUndo(0); err == nil {
+		t.Error("Expected a second undo request to be rejected")
+	}
+	if err := game.DeclineUndo(0); err == nil {
+		t.Error("Expected players not to be able to decline their own undo request")
+	}
+	if err := game.DeclineUndo(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if game.UndoRequest != nil || len(game.Turns) != 1 || game.PlayerIdxWithTurn != 1 {
+		t.Errorf("Expected the move to stand, Was: %+v with %d turns and player %d with the turn",
+			game.UndoRequest, len(game.Turns), game.PlayerIdxWithTurn)
+	}
+	if err := game.ApproveUndo(1); err == nil {
+		t.Error("Expected a declined undo request not to be approvable")
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// Groups of letters whose draw probability is always given
+const (
+	LETTER_GROUP_VOWELS     = "vowels"
+	LETTER_GROUP_CONSONANTS = "consonants"
+	LETTER_GROUP_WILDCARDS  = "wildcards"
+)
+
+type UnseenLetters struct {
+	// Number of each letter that the player can see neither on the board
+	// nor in their own hand, i.e. the letters in the opponents' hands
+	// and in the letter set combined
+	Counts map[string]int
+	Total  int
+
+	// Number of letters left to draw in the letter set
+	LetterSetSize int
+
+	// Chance of each letter to be the next one drawn from the
+	// letter set, as far as the player can tell.
+	// Empty once the letter set is empty.
+	DrawProbabilities map[string]float64
+
+	// The same for the standard LETTER_GROUPs
+	// and for the groups of letters that have been asked for
+	GroupDrawProbabilities map[string]float64
+}
+
+func (game *Game) getRackOfPlayer(playerIdx int) []Letter {
+	// In duplicate games all players share the rack of the round
+	if game.IsDuplicateGame() {
+		round, err := game.GetCurrentDuplicateRound()
+		if err != nil || round.IsFinished {
+			return nil
+		}
+		return round.Rack
+	}
+	return game.Players[playerIdx].LettersInHand
+}
+
+func getLetterGroupCharacters(letterGroup string) (string, error) {
+	// Return the characters of a LETTER_GROUP or, for any other group,
+	// the characters of the group itself
+
+	switch letterGroup {
+	case LETTER_GROUP_VOWELS:
+		return VOWELS, nil
+	case LETTER_GROUP_CONSONANTS:
+		var consonants strings.Builder
+		for character := range letterDistribution {
+			if character != WILDCARD_CHARACTER && !IsVowel(character) {
+				consonants.WriteRune(character)
+			}
+		}
+		return consonants.String(), nil
+	case LETTER_GROUP_WILDCARDS:
+		return string(WILDCARD_CHARACTER), nil
+	}
+
+	characters := strings.ToLower(letterGroup)
+	for _, character := range characters {
+		if _, isInAlphabet := letterDistribution[character]; !isInAlphabet {
+			return "", errors.New("Unknown letter " + string(character) + " in group " + letterGroup)
+		}
+	}
+	return characters, nil
+}
+
+func (game *Game) GetUnseenLetters(playerIdx int, letterGroups []string) (UnseenLetters, error) {
+	// Track the letters the player with the given index has not seen
+	// Requires:
+	// - Groups of letters, e.g. "s*", whose draw probability
+	//   is to be given in addition to the standard LETTER_GROUPs
+	// Guarantees:
+	// - The letters are counted from the full letter distribution
+	//   minus the letters on the board and in the player's hand.
+	//   Wildcard letters are counted as such, whatever they stand for.
+	// - Every letter of the distribution is listed, also with a count of 0
+	// - Return an error for an unknown player or a group
+	//   with a letter that is not in the alphabet
+
+	if playerIdx < 0 || playerIdx >= len(game.Players) {
+		return UnseenLetters{}, errors.New("Unknown player.")
+	}
+
+	unseenCounts := make(map[rune]int)
+	for character, letterAttributes := range letterDistribution {
+		unseenCounts[character] = letterAttributes.Occurrences
+	}
+	for _, tileRow := range game.Tiles {
+		for _, tile := range tileRow {
+			if tile.Letter != (Letter{}) {
+				unseenCounts[tile.Letter.Character]--
+			}
+		}
+	}
+	for _, letter := range game.getRackOfPlayer(playerIdx) {
+		unseenCounts[letter.Character]--
+	}
+
+	unseenLetters := UnseenLetters{
+		Counts:                 make(map[string]int),
+		LetterSetSize:          len(game.LetterSet),
+		DrawProbabilities:      make(map[string]float64),
+		GroupDrawProbabilities: make(map[string]float64),
+	}
+	for character, count := range unseenCounts {
+		unseenLetters.Counts[string(character)] = count
+		unseenLetters.Total += count
+	}
+
+	letterGroups = append([]string{LETTER_GROUP_VOWELS, LETTER_GROUP_CONSONANTS, LETTER_GROUP_WILDCARDS}, letterGroups...)
+	groupCharacters := make(map[string]string)
+	for _, letterGroup := range letterGroups {
+		characters, err := getLetterGroupCharacters(letterGroup)
+		if err != nil {
+			return UnseenLetters{}, err
+		}
+		groupCharacters[letterGroup] = characters
+	}
+
+	// Every unseen letter is as likely as any other to be the next one drawn
+	if unseenLetters.LetterSetSize == 0 || unseenLetters.Total == 0 {
+		return unseenLetters, nil
+	}
+
+	for character, count := range unseenCounts {
+		unseenLetters.DrawProbabilities[string(character)] = float64(count) / float64(unseenLetters.Total)
+	}
+	for letterGroup, characters := range groupCharacters {
+		groupCount := 0
+		for character, count := range unseenCounts {
+			if strings.ContainsRune(characters, character) {
+				groupCount += count
+			}
+		}
+		unseenLetters.GroupDrawProbabilities[letterGroup] = float64(groupCount) / float64(unseenLetters.Total)
+	}
+
+	return unseenLetters, nil
+}
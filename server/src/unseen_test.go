@@ -0,0 +1,55 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetUnseenLettersExcludesBoardAndOwnHand(t *testing.T) {
+
+	letterSet, err := GetFullLetterSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game := Game{Tiles: GetCleanTiles(), Players: []Player{{}, {}}}
+	wildcardLetter := Letter{Character: WILDCARD_CHARACTER, DesignatedCharacter: 'e'}
+	game.Tiles[7][7].Letter = Letter{Character: 'q'}
+	game.Tiles[7][8].Letter = wildcardLetter
+	game.Players[0].LettersInHand = []Letter{{Character: 's'}, {Character: 's'}}
+	game.Players[1].LettersInHand = []Letter{{Character: 'z'}}
+	game.LetterSet = letterSet[:10]
+
+	unseenLetters, err := game.GetUnseenLetters(0, []string{"S*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCounts := map[string]int{"q": 0, "*": 1, "s": 2, "z": 1, "e": 12}
+	for character, expectedCount := range expectedCounts {
+		if unseenLetters.Counts[character] != expectedCount {
+			t.Errorf("Expected %d unseen %s, Was: %d", expectedCount, character, unseenLetters.Counts[character])
+		}
+	}
+	if unseenLetters.Total != lettersAmount-4 || unseenLetters.LetterSetSize != 10 {
+		t.Errorf("Expected %d unseen letters and 10 in the letter set, Was: %d and %d",
+			lettersAmount-4, unseenLetters.Total, unseenLetters.LetterSetSize)
+	}
+
+	expectedProbability := 3.0 / float64(lettersAmount-4)
+	if math.Abs(unseenLetters.GroupDrawProbabilities["S*"]-expectedProbability) > 1e-9 {
+		t.Errorf("Expected a chance of %f to draw S or a wildcard, Was: %f",
+			expectedProbability, unseenLetters.GroupDrawProbabilities["S*"])
+	}
+
+	groupProbabilities := unseenLetters.GroupDrawProbabilities
+	if total := groupProbabilities[LETTER_GROUP_VOWELS] + groupProbabilities[LETTER_GROUP_CONSONANTS] +
+		groupProbabilities[LETTER_GROUP_WILDCARDS]; math.Abs(total-1) > 1e-9 {
+		t.Errorf("Expected the standard letter groups to cover every letter, Was: %f", total)
+	}
+
+	_, err = game.GetUnseenLetters(0, []string{"a1"})
+	if err == nil {
+		t.Error("Expected a group with an unknown letter to be rejected")
+	}
+}
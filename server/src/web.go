@@ -2,19 +2,65 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 )
 
+// Maximum time a long poll request is held open before
+// it is answered with the unchanged state
+var LONG_POLL_TIMEOUT = 30 * time.Second
+
+// Interval in which a comment line is sent over an idle event stream
+// so that proxies do not consider the connection dead
+var EVENT_STREAM_KEEPALIVE_INTERVAL = 15 * time.Second
+
+const GAME_VERSION_HEADER = "X-Gole-Game-Version"
+
+// Carries the PLACEMENT_ERROR code when a move is rejected
+// because of how its letters have been placed
+const PLACEMENT_ERROR_HEADER = "X-Gole-Placement-Error"
+
 type CreateNewGameRequestBody struct {
-	PlayerNames []string
+	// Names of guest players, only used if no Seats are given
+	PlayerNames   []string
+	Seats         []Seat
+	Ruleset       string
+	Variant       string
+	ChallengeRule string
+	IsCasual      bool
+	TimeControl   TimeControl
+	AllowsUndo    bool
+
+	// Hours after which an inactive player with the turn
+	// is resigned, never if omitted
+	InactivityHours int
+
+	// Optional, games with the same seed, ruleset and players
+	// draw the same letters
+	Seed *int64
+}
+
+type AccountCredentialsRequestBody struct {
+	Username string
+	Password string
 }
 
 type SortHandRequestBody struct {
 	LetterIds []string
-	GameId    string
+
+	// One of the HAND_SORT_MODES, used instead of the LetterIds
+	Mode string
+
+	// Whether the Mode is to be applied after every refill as well.
+	// An empty Mode then stops arranging the hand after refills.
+	IsPreferredMode bool
+
+	GameId string
 }
 
 type ReplaceWildcardRequestBody struct {
@@ -27,8 +73,9 @@ type PlaceLetterRequestBody struct {
 	TileXCoordinate int
 	TileYCoordinate int
 	LetterId        string
-	IsWildcard      bool
-	GameId          string
+	// Letter a wildcard letter is placed as, omitted for other letters
+	WildcardCharacter rune
+	GameId            string
 }
 
 type RemoveLetterRequestBody struct {
@@ -37,10 +84,108 @@ type RemoveLetterRequestBody struct {
 	GameId          string
 }
 
+type RecallLettersRequestBody struct {
+	GameId string
+}
+
+type MoveLetterRequestBody struct {
+	FromTileXCoordinate int
+	FromTileYCoordinate int
+	ToTileXCoordinate   int
+	ToTileYCoordinate   int
+	GameId              string
+}
+
+type SwapLetterRequestBody struct {
+	TileXCoordinate int
+	TileYCoordinate int
+	// Letter from the hand that is put on the tile instead
+	LetterId string
+	// Letter a wildcard letter is placed as, omitted for other letters
+	WildcardCharacter rune
+	GameId            string
+}
+
 type ConfirmWordRequestBody struct {
 	GameId string
 }
 
+type JoinGameRequestBody struct {
+	Name        string
+	AsSpectator bool
+	GameId      string
+}
+
+type JoinGameResponse struct {
+	ParticipantId string
+	PlayerIdx     int
+	MemberIdx     int
+	Token         string
+}
+
+type PostChatMessageRequestBody struct {
+	Text   string
+	ToTeam bool
+	GameId string
+}
+
+type MuteParticipantRequestBody struct {
+	ParticipantId string
+	IsMuted       bool
+	GameId        string
+}
+
+type CreateTournamentRequestBody struct {
+	Name           string
+	Format         string
+	NumberOfRounds int
+	Ruleset        string
+	IsCasual       bool
+}
+
+type TournamentRequestBody struct {
+	TournamentId string
+}
+
+type ForfeitTournamentGameRequestBody struct {
+	TournamentId string
+	AccountId    string
+}
+
+type DirectorCorrectionRequestBody struct {
+	GameId    string
+	PlayerIdx int
+	Reason    string
+
+	// New points of the player for score corrections
+	Points int
+
+	// Character of the letter to hand back to the player
+	Letter rune
+}
+
+type SubmitDuplicateMoveRequestBody struct {
+	Placements []MovePlacement
+	GameId     string
+}
+
+type ResignRequestBody struct {
+	GameId string
+}
+
+type UndoRequestBody struct {
+	GameId string
+}
+
+type ChallengeRequestBody struct {
+	GameId string
+}
+
+type PlayMoveRequestBody struct {
+	GameId string
+	Move
+}
+
 type ConfirmWordResponse struct {
 	GainedPoints int
 	Words        []string
@@ -48,22 +193,78 @@ type ConfirmWordResponse struct {
 
 func CreateNewGameHandler(responseWriter http.ResponseWriter, request *http.Request) {
 	//Requires:
-	// - stringified json obect with one key 'PlayerNames'
-	//   that has an array of strings as value, with the player names
+	// - stringified json obect with either the key 'Seats'
+	//   that has an array of Seat objects as value,
+	//   each with either an AccountId or a GuestName,
+	//   or with the key 'PlayerNames'
+	//   that has an array of strings as value, with the names of guests
+	// - Optionally the keys 'Ruleset', 'Variant', 'ChallengeRule' and
+	//   'IsCasual' whereas casual games are not rated. For team games
+	//   every seat needs a 'TeamName'.
+	// - Optionally the key 'Seed', an integer, for casual games. Games with
+	//   the same seed, ruleset and players get the same letter set and draws.
+	// - Optionally the key 'InactivityHours', the hours after which the
+	//   player with the turn is resigned if they do not make any request
+	// - Optionally the session token of the creator's account in the
+	//   Authorization header, which makes the account the game's creator.
+	//   Games with account seats can only be created by one of their accounts.
 	// Guarantees:
 	// - String response with new game ID
+	// - HTTP 401 if a token is sent that is not a valid session token or
+	//   if accounts are seated without the session of one of them
+
+	creator, err := GetAccountForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+	creatorAccountId := ""
+	if creator != nil {
+		creatorAccountId = creator.Id
+	}
 
 	requestBodyDecoder := json.NewDecoder(request.Body)
 	var requestBody CreateNewGameRequestBody
-	var err error
 	err = requestBodyDecoder.Decode(&requestBody)
 	if err != nil {
 		http.Error(responseWriter, err.Error(), 500)
 		return
 	}
 
+	seats := requestBody.Seats
+	if len(seats) == 0 {
+		for _, playerName := range requestBody.PlayerNames {
+			seats = append(seats, Seat{GuestName: playerName})
+		}
+	}
+
+	// Otherwise anyone could seat other accounts in rated games
+	hasAccountSeats, isCreatorSeated := false, false
+	for _, seat := range seats {
+		hasAccountSeats = hasAccountSeats || seat.AccountId != ""
+		isCreatorSeated = isCreatorSeated || (seat.AccountId != "" && seat.AccountId == creatorAccountId)
+	}
+	if hasAccountSeats && !isCreatorSeated {
+		http.Error(responseWriter, "Games with account seats can only be created by one of the seated accounts.", 401)
+		return
+	}
+
+	options := GameOptions{
+		Ruleset:         requestBody.Ruleset,
+		Variant:         requestBody.Variant,
+		ChallengeRule:   requestBody.ChallengeRule,
+		IsCasual:        requestBody.IsCasual,
+		TimeControl:     requestBody.TimeControl,
+		AllowsUndo:      requestBody.AllowsUndo,
+		InactivityHours: requestBody.InactivityHours,
+	}
+	if requestBody.Seed != nil {
+		options.IsSeeded = true
+		options.Seed = *requestBody.Seed
+	}
+
 	var gameId string
-	gameId, err = StartNewGame(requestBody.PlayerNames...)
+	gameId, err = StartNewGame(options, creatorAccountId, seats...)
 	if err != nil {
 		http.Error(responseWriter, err.Error(), 500)
 		return
@@ -77,16 +278,24 @@ func GetBoardHandler(responseWriter http.ResponseWriter, request *http.Request)
 	// Get the board of a Game as JSON
 	// Requires:
 	// - An incoming GET request with an ID in the request Path
+	// - Optionally a sinceVersion query parameter with the last version
+	//   of the game the client knows about
 	// Guarantees:
 	// - Return a json object that represents the board of the game with
 	//   the given ID similar to how the Board struct on the server
 	//   side represents it.
+	// - If sinceVersion is given, the response is held back until
+	//   the version of the game has moved past it or until the
+	//   long poll timeout has expired, whichever comes first.
+	//   The board is returned in both cases.
+	// - The current version of the game is returned in the
+	//   X-Gole-Game-Version header
 
 	id := mux.Vars(request)["id"]
 
 	var err error
 	var game *Game
-	game, err = GetGameByUUID(id)
+	game, err = GetReadableGameByUUID(id)
 
 	if err != nil {
 		log.Println("Not a valid GameID: ", id)
@@ -94,6 +303,27 @@ func GetBoardHandler(responseWriter http.ResponseWriter, request *http.Request)
 		return
 	}
 
+	sinceVersionParameter := request.URL.Query().Get("sinceVersion")
+	if sinceVersionParameter != "" {
+		sinceVersion, err := strconv.Atoi(sinceVersionParameter)
+		if err != nil {
+			http.Error(responseWriter, "Invalid sinceVersion", 400)
+			return
+		}
+		// The game must not be locked while waiting,
+		// otherwise no one could change it
+		game.Acquire()
+		notifier := GetGameChangeNotifier(game)
+		game.Release()
+		notifier.WaitForVersionAfter(
+			sinceVersion, LONG_POLL_TIMEOUT, request.Context().Done())
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	responseWriter.Header().Set(GAME_VERSION_HEADER, strconv.Itoa(game.Version))
+
 	var boardJson []byte
 	boardJson, err = json.Marshal(game.Tiles)
 	if err != nil {
@@ -112,12 +342,13 @@ func GetPotentialPointsHandler(responseWriter http.ResponseWriter, request *http
 	// Guarantees:
 	// - Return a JSON list containing PotentialPointsForWord Structs
 	//   whereas each of these structs contains the start and end coordinates
-	//   for an unconfirmed word on the board and the points a player could
-	//   gain for playing this word (assuming it is a valid word)
+	//   for an unconfirmed word on the board, the word in standard notation
+	//   and the points a player could gain for playing this word
+	//   (assuming it is a valid word)
 
 	id := mux.Vars(request)["id"]
 
-	game, err := GetGameByUUID(id)
+	game, err := GetReadableGameByUUID(id)
 
 	if err != nil {
 		log.Println("Not a valid GameID: ", id)
@@ -125,6 +356,9 @@ func GetPotentialPointsHandler(responseWriter http.ResponseWriter, request *http
 		return
 	}
 
+	game.Acquire()
+	defer game.Release()
+
 	potentialPointsForWords, err := GetPotentialPoints(game)
 
 	if err != nil {
@@ -153,12 +387,19 @@ func SortHandHandler(responseWriter http.ResponseWriter, request *http.Request)
 	//   it must contain a slice of letterIDs of letters from the
 	//   active players hand. The amount of letterIDs must match
 	//   the actual number of letters in the active players hand.
+	// - In games with account seats the token of the player
+	//   with the turn in the Authorization header
 	// Guarantees:
 	// - If the LetterIDs value is empty, the letters in the active players
 	//   hands are shuffled randomly and stored back to the player's hand.
 	// - If the LatterIDs value is valid and non-empty, the letters in
 	//   the hand of the active player are sorted according to the
 	//   array and stored to the player's hand accordingly.
+	// - If a Mode is given, the letters are arranged by the server
+	//   according to this mode instead. With IsPreferredMode the mode
+	//   is remembered and applied whenever the hand is refilled.
+	//   If the request has been authenticated as the account of the seat,
+	//   the mode is stored on the account for all of its new games too.
 	// - If successful, HTTP 200 and the gameId is returned
 	// - If there is an error in either the request handler or the
 	//   game logic, HTTP 500 and the error message is returned.
@@ -179,6 +420,13 @@ func SortHandHandler(responseWriter http.ResponseWriter, request *http.Request)
 		return
 	}
 
+	game.Acquire()
+	defer game.Release()
+
+	if !authorizePlayerWithTurn(responseWriter, request, game, false) {
+		return
+	}
+
 	var activePlayer *Player
 	activePlayer, err = GetActivePlayer(game)
 	if err != nil {
@@ -186,9 +434,28 @@ func SortHandHandler(responseWriter http.ResponseWriter, request *http.Request)
 		return
 	}
 
-	if requestBody.LetterIds != nil {
+	if requestBody.IsPreferredMode {
+		err = activePlayer.SetPreferredHandSortMode(requestBody.Mode)
+		if err != nil {
+			http.Error(responseWriter, err.Error(), 500)
+			return
+		}
+
+		participant, err := game.GetParticipantForRequest(request)
+		if err == nil && participant.AccountId != "" && participant.AccountId == activePlayer.AccountId {
+			err = SetPreferredHandSortMode(participant.AccountId, requestBody.Mode)
+			if err != nil {
+				http.Error(responseWriter, err.Error(), 500)
+				return
+			}
+		}
+	}
+
+	if requestBody.Mode != "" {
+		err = activePlayer.SortHandByMode(requestBody.Mode)
+	} else if requestBody.LetterIds != nil {
 		err = activePlayer.SortHand(requestBody.LetterIds)
-	} else {
+	} else if !requestBody.IsPreferredMode {
 		activePlayer.ShuffleHand()
 	}
 
@@ -197,6 +464,8 @@ func SortHandHandler(responseWriter http.ResponseWriter, request *http.Request)
 		return
 	}
 
+	game.PublishChange(GAME_EVENT_HAND_CHANGED, nil)
+
 	responseWriter.Write([]byte(requestBody.GameId))
 
 }
@@ -208,9 +477,11 @@ func ReplaceWildcardHandler(responseWriter http.ResponseWriter, request *http.Re
 	// - An incoming HTTP Request Body with values to all keys
 	//   as they are defined in the ReplaceWildcardRequestBody struct
 	//   (matching key name, valid data type)
+	// - In games with account seats the token of the player
+	//   with the turn in the Authorization header
 	// Guarantees:
-	// - Call the ReplaceWildcard function that will replace the wildcard
-	//   character on a tile with an actual letter
+	// - Call the ReplaceWildcard function that will designate the letter
+	//   a wildcard letter in the hand is going to be placed as
 	// - Will return HTTP 200 and the id of the letter struct
 	//   if the replacement was successful
 	// - Will return HTTP 500 if there has been an error either in the
@@ -232,6 +503,13 @@ func ReplaceWildcardHandler(responseWriter http.ResponseWriter, request *http.Re
 		return
 	}
 
+	game.Acquire()
+	defer game.Release()
+
+	if !authorizePlayerWithTurn(responseWriter, request, game, false) {
+		return
+	}
+
 	var activePlayer *Player
 	activePlayer, err = GetActivePlayer(game)
 	if err != nil {
@@ -246,6 +524,8 @@ func ReplaceWildcardHandler(responseWriter http.ResponseWriter, request *http.Re
 		return
 	}
 
+	game.PublishChange(GAME_EVENT_HAND_CHANGED, nil)
+
 	responseWriter.Write([]byte(requestBody.LetterId))
 
 }
@@ -256,12 +536,16 @@ func PlaceLetterHandler(responseWriter http.ResponseWriter, request *http.Reques
 	// - An incoming HTTP Request Body with values to all keys
 	//   as they are defined in the PlaceLetterRequestBody struct
 	//   (matching key name, valid data type)
+	// - In games with account seats the token of the player
+	//   with the turn in the Authorization header
 	// Guarantees:
 	// - Call the PlaceLetter function that will handle the
 	//   game logic of placing a letter from the player
 	//   hand on a board tile
 	// - Will return with code 200 and the GameID if the letter was
 	//   placed successfully
+	// - Will respond with code 401 if the request could not be
+	//   authenticated in a game with account seats
 	// - Will respond with code 500 if there has been an error in either
 	//   the HTTP request handler function or the game logic function.
 
@@ -281,8 +565,15 @@ func PlaceLetterHandler(responseWriter http.ResponseWriter, request *http.Reques
 		return
 	}
 
+	game.Acquire()
+	defer game.Release()
+
+	if !authorizePlayerWithTurn(responseWriter, request, game, false) {
+		return
+	}
+
 	err = PlaceLetter(game, requestBody.TileYCoordinate,
-		requestBody.TileXCoordinate, requestBody.LetterId)
+		requestBody.TileXCoordinate, requestBody.LetterId, requestBody.WildcardCharacter)
 
 	if err != nil {
 		http.Error(responseWriter, err.Error(), 500)
@@ -310,6 +601,13 @@ func RemoveLetterHandler(responseWriter http.ResponseWriter, request *http.Reque
 		return
 	}
 
+	game.Acquire()
+	defer game.Release()
+
+	if !authorizePlayerWithTurn(responseWriter, request, game, false) {
+		return
+	}
+
 	err = RemoveLetter(game, requestBody.TileYCoordinate, requestBody.TileXCoordinate)
 
 	if err != nil {
@@ -321,13 +619,134 @@ func RemoveLetterHandler(responseWriter http.ResponseWriter, request *http.Reque
 
 }
 
+func RecallLettersHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Take all letters placed in the current turn back to the hand
+	// Guarantees:
+	// - Respond like RemoveLetterHandler
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody RecallLettersRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	game, err := GetGameByUUID(requestBody.GameId)
+	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	if !authorizePlayerWithTurn(responseWriter, request, game, false) {
+		return
+	}
+
+	err = RecallLetters(game)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write([]byte(game.Id))
+}
+
+func MoveLetterHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Move a letter placed in the current turn to another tile
+	// Requires:
+	// - The tile the letter is on and the tile it is moved to
+	//   as in MoveLetterRequestBody
+	// - In games with account seats the token of the player
+	//   with the turn in the Authorization header
+	// Guarantees:
+	// - Respond like PlaceLetterHandler
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody MoveLetterRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	game, err := GetGameByUUID(requestBody.GameId)
+	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	if !authorizePlayerWithTurn(responseWriter, request, game, false) {
+		return
+	}
+
+	err = MoveLetter(game, requestBody.FromTileYCoordinate, requestBody.FromTileXCoordinate,
+		requestBody.ToTileYCoordinate, requestBody.ToTileXCoordinate)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write([]byte(game.Id))
+}
+
+func SwapLetterHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Exchange a letter placed in the current turn with one from the hand
+	// Guarantees:
+	// - Respond like PlaceLetterHandler
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody SwapLetterRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	game, err := GetGameByUUID(requestBody.GameId)
+	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	if !authorizePlayerWithTurn(responseWriter, request, game, false) {
+		return
+	}
+
+	err = SwapLetter(game, requestBody.TileYCoordinate, requestBody.TileXCoordinate,
+		requestBody.LetterId, requestBody.WildcardCharacter)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write([]byte(game.Id))
+}
+
 func ConfirmWordHandler(responseWriter http.ResponseWriter, request *http.Request) {
 	// Trigger the function to finish up the round after a player has placed all
 	// letters for this round.
 	// Requires:
 	// - GameId in Request Body
+	// - In team games the token of the team member whose turn
+	//   it is to submit in the Authorization header, in other games
+	//   with account seats the token of the player with the turn
 	// Guarantees:
-	// - HTTP 500 response if an error occured
+	// - HTTP 401 response if the submitter could not be authenticated
+	// - HTTP 500 response if an error occured. If the placed letters do not
+	//   form one legal move, the reason is given as PLACEMENT_ERROR code
+	//   in the X-Gole-Placement-Error header
 	// - HTTP 200 if the turn has been finished successfully and the next player
 	//   can continue with the game.
 	// - HTTP 250 if the turn has been finished successfully and if the game is
@@ -362,12 +781,19 @@ func ConfirmWordHandler(responseWriter http.ResponseWriter, request *http.Reques
 		return
 	}
 
+	game.Acquire()
+	defer game.Release()
+
+	if !authorizePlayerWithTurn(responseWriter, request, game, true) {
+		return
+	}
+
 	confirmWordResponse := ConfirmWordResponse{}
 	confirmWordResponse.GainedPoints, confirmWordResponse.Words, err = FinishTurn(game)
 
 	if err != nil {
 		log.Println("Not a valid GameID: ", requestBody.GameId)
-		http.Error(responseWriter, err.Error(), HTTP_ERROR_CODE)
+		writeMoveError(responseWriter, err, HTTP_ERROR_CODE)
 		return
 	}
 
@@ -387,86 +813,1585 @@ func ConfirmWordHandler(responseWriter http.ResponseWriter, request *http.Reques
 
 }
 
-func GetActivePlayerHandler(responseWriter http.ResponseWriter, request *http.Request) {
-	id := mux.Vars(request)["id"]
+func authorizePlayerWithTurn(responseWriter http.ResponseWriter, request *http.Request,
+	game *Game, isSubmitting bool) bool {
+	// Make sure that in games with account seats only the participant on
+	// the seat with the turn changes its hand and the board, and that in
+	// team games only the member whose turn it is submits the move
+	// Guarantees:
+	// - Return true if the request may go ahead, which it always
+	//   may in guest games that are not team games
+	// - Otherwise respond with HTTP 401 if the request could not be
+	//   authenticated or HTTP 500 if the participant may not play now,
+	//   and return false
+	// - A player who may go ahead is counted as active, see CheckInactivity
+
+	if !game.HasAccountSeats() && !(isSubmitting && game.IsTeamGame()) {
+		game.markPlayerWithTurnActive()
+		return true
+	}
 
-	var err error
-	game, err := GetGameByUUID(id)
+	participant, err := game.GetParticipantForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return false
+	}
 
+	if isSubmitting {
+		err = game.CheckSubmitter(participant)
+	} else {
+		err = game.CheckPlayerWithTurn(participant)
+	}
 	if err != nil {
-		log.Println("Not a valid GameID: ", id)
 		http.Error(responseWriter, err.Error(), 500)
+		return false
+	}
+	game.markParticipantActive(participant)
+	return true
+}
+
+func writeMoveError(responseWriter http.ResponseWriter, err error, statusCode int) {
+	// Respond with the error of a rejected move so that clients can
+	// tell illegal placements apart without parsing the message
+	placementErrorCode := GetPlacementErrorCode(err)
+	if placementErrorCode != "" {
+		responseWriter.Header().Set(PLACEMENT_ERROR_HEADER, placementErrorCode)
+	}
+	http.Error(responseWriter, err.Error(), statusCode)
+}
+
+func PlayMoveHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Play a whole move in one request instead of placing
+	// letters one by one and confirming them
+	// Requires:
+	// - GameId in Request Body along with either
+	//   -- Placements: letter Ids with their tiles and, for wildcard
+	//      letters, the WildcardCharacter
+	//   -- or StartVerticalIdx, StartHorizontalIdx, Direction and Word,
+	//      optionally with the WildcardIdxs of the word's letters
+	//      that are to be played with wildcard letters
+	//   -- or the Notation of the move, e.g. "8G C(A)t"
+	// - In team games the token of the team member whose turn
+	//   it is to submit in the Authorization header, in other games
+	//   with account seats the token of the player with the turn
+	// Guarantees:
+	// - Respond like ConfirmWordHandler
+	// - The game is not changed at all if the move is rejected
+
+	HTTP_GAME_OVER_CODE := 250
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody PlayMoveRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
 		return
 	}
 
-	var playerList []byte
-	playerList, err = json.Marshal(game.Players)
+	game, err := GetGameByUUID(requestBody.GameId)
 	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
 		http.Error(responseWriter, err.Error(), 500)
 		return
 	}
-	log.Println(string(playerList))
 
-	var activePlayer *Player
-	activePlayer, err = GetActivePlayer(game)
-	if err != nil {
-		http.Error(responseWriter, "Error when trying to retrieve player.", 500)
+	game.Acquire()
+	defer game.Release()
+
+	if !authorizePlayerWithTurn(responseWriter, request, game, true) {
 		return
 	}
 
-	log.Println("The active player's hand::")
-	log.Println(activePlayer.LettersInHand)
+	playMoveResponse := ConfirmWordResponse{}
+	playMoveResponse.GainedPoints, playMoveResponse.Words, err = game.PlayMove(requestBody.Move)
+	if err != nil {
+		writeMoveError(responseWriter, err, 500)
+		return
+	}
 
-	var playerJson []byte
-	playerJson, err = json.Marshal(activePlayer)
+	playMoveResponseJson, err := json.Marshal(playMoveResponse)
 	if err != nil {
 		http.Error(responseWriter, err.Error(), 500)
 		return
 	}
 
-	responseWriter.Write(playerJson)
-
+	if game.GameOver {
+		responseWriter.WriteHeader(HTTP_GAME_OVER_CODE)
+	}
+	responseWriter.Write(playMoveResponseJson)
 }
 
-func GetScoreBoardHandler(responseWriter http.ResponseWriter, request *http.Request) {
-	// Return a json object describing the players of the game with the given
-	// is and their game points.
-	// Keys in the returned object will be the player name
-	// with the points as the value
-
-	id := mux.Vars(request)["id"]
+func ValidateMoveHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Check and score a move without playing it
+	// Requires:
+	// - The same Request Body as for PlayMoveHandler
+	// Guarantees:
+	// - Return a MoveValidation as JSON, also for moves
+	//   that cannot be played
+	// - HTTP 500 and the error message if the game cannot evaluate moves
 
-	var err error
-	game, err := GetGameByUUID(id)
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody PlayMoveRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
 
+	game, err := GetReadableGameByUUID(requestBody.GameId)
 	if err != nil {
-		log.Println("Not a valid GameID: ", id)
+		log.Println("Not a valid GameID: ", requestBody.GameId)
 		http.Error(responseWriter, err.Error(), 500)
 		return
 	}
 
-	log.Println("ok")
+	game.Acquire()
+	defer game.Release()
 
-	var scoreBoard []byte
-	scoreBoard, err = json.Marshal(game.GetScoreBoard())
+	validation, err := game.ValidateMove(requestBody.Move)
 	if err != nil {
 		http.Error(responseWriter, err.Error(), 500)
 		return
 	}
-	responseWriter.Write(scoreBoard)
+
+	validationJson, err := json.Marshal(validation)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(validationJson)
+}
+
+func GetActivePlayerHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	id := mux.Vars(request)["id"]
+
+	var err error
+	game, err := GetReadableGameByUUID(id)
+
+	if err != nil {
+		log.Println("Not a valid GameID: ", id)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	var playerList []byte
+	playerList, err = json.Marshal(game.Players)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+	log.Println(string(playerList))
+
+	var activePlayer *Player
+	activePlayer, err = GetActivePlayer(game)
+	if err != nil {
+		http.Error(responseWriter, "Error when trying to retrieve player.", 500)
+		return
+	}
+
+	log.Println("The active player's hand::")
+	log.Println(activePlayer.LettersInHand)
+
+	// The clock is filled in on a copy, a GET request
+	// must not change the game
+	shownPlayer := *activePlayer
+	shownPlayer.Clock = game.GetCurrentClock(game.PlayerIdxWithTurn)
+
+	var playerJson []byte
+	playerJson, err = json.Marshal(shownPlayer)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(playerJson)
+
+}
+
+func GetUnseenLettersHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Return the letters the requesting player has not seen yet
+	// Requires:
+	// - An incoming GET request with an ID in the request Path
+	// - The token of a player of the game in the Authorization header
+	// - Optionally one or more query parameters "letters", each a group
+	//   of letters whose chance to be drawn next is to be given, e.g. "s*"
+	// Guarantees:
+	// - Return UnseenLetters as JSON
+	// - HTTP 401 if the request could not be authenticated
+	//   or comes from a spectator
+	// - HTTP 400 for a group with letters that are not in the alphabet
+
+	id := mux.Vars(request)["id"]
+
+	game, err := GetReadableGameByUUID(id)
+	if err != nil {
+		log.Println("Not a valid GameID: ", id)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	participant, err := game.GetParticipantForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+	if participant.IsSpectator() {
+		http.Error(responseWriter, "Spectators have no letters of their own.", 401)
+		return
+	}
+
+	unseenLetters, err := game.GetUnseenLetters(participant.PlayerIdx, request.URL.Query()["letters"])
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 400)
+		return
+	}
+
+	unseenLettersJson, err := json.Marshal(unseenLetters)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(unseenLettersJson)
+}
+
+func GetScoreBoardHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Return a json object describing the players of the game with the given
+	// is and their game points.
+	// Keys in the returned object will be the player name
+	// with the points as the value.
+	// With the query parameter detailed=true a list of ScoreBoardEntry
+	// is returned instead, including the time left on the players' clocks.
+
+	id := mux.Vars(request)["id"]
+
+	var err error
+	game, err := GetReadableGameByUUID(id)
+
+	if err != nil {
+		log.Println("Not a valid GameID: ", id)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	log.Println("ok")
+
+	var scoreBoard []byte
+	if request.URL.Query().Get("detailed") == "true" {
+		scoreBoard, err = json.Marshal(game.GetDetailedScoreBoard())
+	} else {
+		scoreBoard, err = json.Marshal(game.GetScoreBoard())
+	}
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+	responseWriter.Write(scoreBoard)
+}
+
+func writeGameEventToStream(responseWriter http.ResponseWriter, event GameEvent) error {
+	// Write a single event in the Server-Sent Events format
+
+	eventJson, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(responseWriter, "id: %d\nevent: %s\ndata: %s\n\n",
+		event.Version, event.Type, eventJson)
+	return err
+}
+
+func GetGameEventsHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Stream the changes of a game as Server-Sent Events
+	// Requires:
+	// - An incoming GET request with an ID in the request Path
+	// - Optionally a Last-Event-ID header (sent automatically by browsers
+	//   when reconnecting) with the version of the last received event
+	// Guarantees:
+	// - Send a hello event with the current version right away
+	// - Send every event that is published for the game afterwards
+	//   whereas the event id is the version of the game after the change
+	// - Send a resync event if events have been missed and can not be
+	//   replayed, in which case the client needs to reload the full state
+	// - Private events (e.g. team chat) are only sent to clients that
+	//   have authenticated as a participant they are meant for,
+	//   chat messages only to clients that have authenticated at all
+	// - The stream is served from the request's own goroutine and
+	//   ends as soon as the client disconnects
+
+	id := mux.Vars(request)["id"]
+
+	game, err := GetReadableGameByUUID(id)
+	if err != nil {
+		log.Println("Not a valid GameID: ", id)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	flusher, ok := responseWriter.(http.Flusher)
+	if !ok {
+		http.Error(responseWriter, "Streaming is not supported.", 500)
+		return
+	}
+
+	// Authentication is optional for the stream. The game is only
+	// locked while the viewer is looked up, not while streaming,
+	// so the viewer is copied out of the game.
+	var viewer *Participant
+	game.Acquire()
+	participant, err := game.GetParticipantForRequest(request)
+	if err == nil {
+		viewerCopy := *participant
+		viewer = &viewerCopy
+	}
+	notifier := GetGameChangeNotifier(game)
+	game.Release()
+	lastSentVersion := notifier.GetVersion()
+
+	lastEventId := request.Header.Get("Last-Event-ID")
+	if lastEventId != "" {
+		lastSentVersion, err = strconv.Atoi(lastEventId)
+		if err != nil {
+			http.Error(responseWriter, "Invalid Last-Event-ID", 400)
+			return
+		}
+	}
+
+	responseWriter.Header().Set("Content-Type", "text/event-stream")
+	responseWriter.Header().Set("Cache-Control", "no-cache")
+	responseWriter.Header().Set("Connection", "keep-alive")
+	responseWriter.WriteHeader(200)
+
+	err = writeGameEventToStream(responseWriter, GameEvent{
+		Version: lastSentVersion,
+		Type:    GAME_EVENT_STREAM_PRELUDE,
+	})
+	if err != nil {
+		return
+	}
+	flusher.Flush()
+
+	keepAliveTicker := time.NewTicker(EVENT_STREAM_KEEPALIVE_INTERVAL)
+	defer keepAliveTicker.Stop()
+
+	for {
+		currentVersion, changed := notifier.GetChangedChannel()
+
+		if currentVersion > lastSentVersion {
+			events, isComplete := notifier.GetEventsSince(lastSentVersion)
+			if !isComplete {
+				events = []GameEvent{{Version: currentVersion, Type: GAME_EVENT_STREAM_RESYNC}}
+			}
+			for _, event := range events {
+				if event.IsVisibleTo(viewer) {
+					err = writeGameEventToStream(responseWriter, event)
+					if err != nil {
+						return
+					}
+				}
+				lastSentVersion = event.Version
+			}
+			flusher.Flush()
+			continue
+		}
+
+		select {
+		case <-changed:
+		case <-keepAliveTicker.C:
+			_, err = fmt.Fprint(responseWriter, ": keepalive\n\n")
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-request.Context().Done():
+			return
+		}
+	}
+}
+
+func JoinGameHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Join a game as a player or as a spectator
+	// Requires:
+	// - An incoming HTTP Request Body with values to all keys
+	//   as they are defined in the JoinGameRequestBody struct.
+	//   Unless joining as a spectator, the name must be the name
+	//   of a player seat that has not been claimed yet.
+	// - Optionally the session token of an account in the
+	//   Authorization header, which is required to claim a seat
+	//   that is linked to an account
+	// Guarantees:
+	// - Return a JoinGameResponse as JSON, whereas the contained token
+	//   needs to be sent as "Authorization: Bearer <token>" header
+	//   with every request that requires authentication.
+	// - HTTP 500 and the error message if joining failed
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody JoinGameRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	game, err := GetGameByUUID(requestBody.GameId)
+	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	account, err := GetAccountForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+
+	participant, err := game.JoinGame(requestBody.Name, requestBody.AsSpectator, account)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	joinGameResponseJson, err := json.Marshal(JoinGameResponse{
+		ParticipantId: participant.Id,
+		PlayerIdx:     participant.PlayerIdx,
+		MemberIdx:     participant.MemberIdx,
+		Token:         participant.Token,
+	})
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(joinGameResponseJson)
+}
+
+func PostChatMessageHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Post a chat message to a game
+	// Requires:
+	// - An incoming HTTP Request Body with values to all keys
+	//   as they are defined in the PostChatMessageRequestBody struct
+	// - The token of a participant of the game in the Authorization header
+	// Guarantees:
+	// - Return the stored chat message as JSON
+	// - HTTP 401 if the request could not be authenticated
+	// - HTTP 500 and the error message if the message was rejected
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody PostChatMessageRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	game, err := GetGameByUUID(requestBody.GameId)
+	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	participant, err := game.GetParticipantForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+
+	chatMessage, err := game.PostChatMessage(participant, requestBody.Text, requestBody.ToTeam)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	chatMessageJson, err := json.Marshal(chatMessage)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(chatMessageJson)
+}
+
+func GetChatHistoryHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Get one page of the chat history of a game
+	// Requires:
+	// - An incoming GET request with an ID in the request Path
+	// - The token of a participant of the game in the Authorization header
+	// - Optionally the query parameters "before" (sequence number of the
+	//   oldest message already known to the client) and "limit"
+	// Guarantees:
+	// - Return a ChatHistoryPage as JSON
+	// - HTTP 401 if the request could not be authenticated
+
+	id := mux.Vars(request)["id"]
+
+	game, err := GetReadableGameByUUID(id)
+	if err != nil {
+		log.Println("Not a valid GameID: ", id)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	reader, err := game.GetParticipantForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+
+	var beforeSeq, limit int
+	if beforeParameter := request.URL.Query().Get("before"); beforeParameter != "" {
+		beforeSeq, err = strconv.Atoi(beforeParameter)
+		if err != nil {
+			http.Error(responseWriter, "Invalid before parameter", 400)
+			return
+		}
+	}
+	if limitParameter := request.URL.Query().Get("limit"); limitParameter != "" {
+		limit, err = strconv.Atoi(limitParameter)
+		if err != nil {
+			http.Error(responseWriter, "Invalid limit parameter", 400)
+			return
+		}
+	}
+
+	chatHistoryJson, err := json.Marshal(game.GetChatHistory(reader, beforeSeq, limit))
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(chatHistoryJson)
+}
+
+func MuteParticipantHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Mute or unmute a participant of a game
+	// Requires:
+	// - An incoming HTTP Request Body with values to all keys
+	//   as they are defined in the MuteParticipantRequestBody struct
+	// - The token of the game's creator, i.e. of a participant logged in
+	//   with the account that has created the game, in the Authorization header
+	// Guarantees:
+	// - Return HTTP 200 and the participant id if successful
+	// - HTTP 401 if the request could not be authenticated
+	// - HTTP 500 and the error message otherwise
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody MuteParticipantRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	game, err := GetGameByUUID(requestBody.GameId)
+	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	moderator, err := game.GetParticipantForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+
+	err = game.SetParticipantMuted(moderator, requestBody.ParticipantId, requestBody.IsMuted)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write([]byte(requestBody.ParticipantId))
+}
+
+func RegisterAccountHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Register a new account
+	// Requires:
+	// - An incoming HTTP Request Body with values to all keys
+	//   as they are defined in the AccountCredentialsRequestBody struct
+	// Guarantees:
+	// - Return the new account as JSON (without the password hash)
+	// - HTTP 500 and the error message if the registration failed
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody AccountCredentialsRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	account, err := RegisterAccount(requestBody.Username, requestBody.Password)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	accountJson, err := json.Marshal(account)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(accountJson)
+}
+
+func LoginHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Log in to an account
+	// Requires:
+	// - An incoming HTTP Request Body with values to all keys
+	//   as they are defined in the AccountCredentialsRequestBody struct
+	// Guarantees:
+	// - Return the new AccountSession as JSON, whereas its token needs
+	//   to be sent as "Authorization: Bearer <token>" header with every
+	//   request that is to be made on behalf of the account
+	// - HTTP 401 if the credentials are invalid
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody AccountCredentialsRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	session, err := Login(requestBody.Username, requestBody.Password)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+
+	sessionJson, err := json.Marshal(session)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(sessionJson)
+}
+
+func LogoutHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// End the session whose token is sent in the Authorization header
+
+	err := Logout(GetRequestToken(request))
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+}
+
+func GetPlayerRatingHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Get the ratings of a player
+	// Requires:
+	// - An incoming GET request with an account ID in the request Path
+	// Guarantees:
+	// - Return a JSON list with one PlayerRating struct, including
+	//   the rating history, per ruleset the player has been rated in
+
+	id := mux.Vars(request)["id"]
+
+	_, err := GetAccountById(id)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	playerRatingsJson, err := json.Marshal(GetPlayerRatings(id))
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(playerRatingsJson)
+}
+
+func GetPlayerStatisticsHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Get the lifetime statistics of a player
+	// Requires:
+	// - An incoming GET request with an account ID in the request Path
+	// Guarantees:
+	// - Return the PlayerStatistics struct as JSON,
+	//   computed from all finished games of the player
+
+	id := mux.Vars(request)["id"]
+
+	_, err := GetAccountById(id)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	statistics := GetPlayerStatistics(id)
+
+	statisticsJson, err := json.Marshal(statistics)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(statisticsJson)
+}
+
+func GetPlayerStatisticsCSVHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Get the lifetime statistics of a player as CSV file
+	// Requires:
+	// - An incoming GET request with an account ID in the request Path
+	// Guarantees:
+	// - Return the statistics as CSV with the columns Statistic,
+	//   Value and Detail, one row per statistic
+	//   and one row per frequently played word
+
+	id := mux.Vars(request)["id"]
+
+	_, err := GetAccountById(id)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	statistics := GetPlayerStatistics(id)
+
+	responseWriter.Header().Set("Content-Type", "text/csv")
+	responseWriter.Header().Set("Content-Disposition", "attachment; filename=\"statistics.csv\"")
+
+	err = statistics.WriteCSV(responseWriter)
+	if err != nil {
+		log.Println("Could not write statistics CSV: ", err)
+	}
+}
+
+func GetGameHistoryHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Get the record of all finished turns of a game
+	// Requires:
+	// - An incoming GET request with an ID in the request Path
+	// Guarantees:
+	// - Return a JSON list of TurnRecord structs, oldest turn first
+
+	id := mux.Vars(request)["id"]
+
+	game, err := GetReadableGameByUUID(id)
+	if err != nil {
+		log.Println("Not a valid GameID: ", id)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	historyJson, err := json.Marshal(game.Turns)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(historyJson)
+}
+
+func GetBagProofHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Get the commitment to the order of the letter set of a game
+	// Requires:
+	// - An incoming GET request with an ID in the request Path
+	// Guarantees:
+	// - Return a BagProof as JSON, which only reveals the salt,
+	//   the initial order and the changes once the game is over
+
+	id := mux.Vars(request)["id"]
+
+	game, err := GetReadableGameByUUID(id)
+	if err != nil {
+		log.Println("Not a valid GameID: ", id)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	bagProofJson, err := json.Marshal(game.GetBagProof())
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(bagProofJson)
+}
+
+func VerifyBagHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Check that every draw of a finished game matches
+	// the order of the letter set it has committed to
+	// Requires:
+	// - An incoming GET request with an ID in the request Path
+	// Guarantees:
+	// - Return a BagVerification as JSON
+	// - HTTP 400 if the game is not over yet
+
+	id := mux.Vars(request)["id"]
+
+	game, err := GetReadableGameByUUID(id)
+	if err != nil {
+		log.Println("Not a valid GameID: ", id)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	verification, err := game.VerifyBag()
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 400)
+		return
+	}
+
+	verificationJson, err := json.Marshal(verification)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(verificationJson)
+}
+
+func getLoggedInAccount(responseWriter http.ResponseWriter, request *http.Request) (*Account, bool) {
+	// Return the account whose session token has been sent with the request
+	// Guarantees:
+	// - Respond with HTTP 401 and return false if there is none
+
+	account, err := GetAccountForRequest(request)
+	if err != nil || account == nil {
+		http.Error(responseWriter, "Login required.", 401)
+		return nil, false
+	}
+	return account, true
+}
+
+func CreateTournamentHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Create a new tournament
+	// Requires:
+	// - An incoming HTTP Request Body with values to all keys
+	//   as they are defined in the CreateTournamentRequestBody struct
+	//   whereas NumberOfRounds is only needed for swiss tournaments
+	// - The session token of the director's account
+	//   in the Authorization header
+	// Guarantees:
+	// - Return the new tournament as JSON
+
+	account, ok := getLoggedInAccount(responseWriter, request)
+	if !ok {
+		return
+	}
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody CreateTournamentRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	tournament, err := CreateTournament(*account, requestBody.Name, requestBody.Format,
+		requestBody.NumberOfRounds, GameOptions{
+			Ruleset:  requestBody.Ruleset,
+			IsCasual: requestBody.IsCasual,
+		})
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	tournamentJson, err := json.Marshal(tournament)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(tournamentJson)
+}
+
+func GetTournamentsHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Return a JSON list of all tournaments, the most recent first
+
+	tournamentsJson, err := json.Marshal(GetTournaments())
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(tournamentsJson)
+}
+
+func GetTournamentPairingsHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Return the rounds of a tournament with all their pairings,
+	// the game Ids and results of the finished games as JSON
+
+	id := mux.Vars(request)["id"]
+
+	tournament, err := GetTournamentById(id)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	roundsJson, err := json.Marshal(tournament.Rounds)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(roundsJson)
+}
+
+func GetTournamentStandingsHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Return the current standings of a tournament as a JSON list
+	// of Standing structs, ranked by wins and then by spread
+
+	id := mux.Vars(request)["id"]
+
+	standings, err := GetTournamentStandings(id)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	standingsJson, err := json.Marshal(standings)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(standingsJson)
+}
+
+func RegisterForTournamentHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Register the logged in account for a tournament
+	// Requires:
+	// - A TournamentRequestBody
+	// - The session token of the account in the Authorization header
+
+	account, ok := getLoggedInAccount(responseWriter, request)
+	if !ok {
+		return
+	}
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody TournamentRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	err = RegisterForTournament(requestBody.TournamentId, *account)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write([]byte(requestBody.TournamentId))
+}
+
+func StartTournamentHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Close the registration of a tournament and create the
+	// games of the first round
+	// Requires:
+	// - A TournamentRequestBody
+	// - The session token of the director in the Authorization header
+
+	account, ok := getLoggedInAccount(responseWriter, request)
+	if !ok {
+		return
+	}
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody TournamentRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	err = StartTournament(requestBody.TournamentId, *account)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write([]byte(requestBody.TournamentId))
+}
+
+func ForfeitTournamentGameHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Record that a player forfeits their game of the current round
+	// Requires:
+	// - A ForfeitTournamentGameRequestBody
+	// - The session token of the director in the Authorization header
+
+	account, ok := getLoggedInAccount(responseWriter, request)
+	if !ok {
+		return
+	}
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody ForfeitTournamentGameRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	err = ForfeitTournamentGame(requestBody.TournamentId, *account, requestBody.AccountId)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write([]byte(requestBody.TournamentId))
+}
+
+func handleDirectorCorrection(responseWriter http.ResponseWriter, request *http.Request,
+	correct func(game *Game, director Account, requestBody DirectorCorrectionRequestBody) (DirectorCorrection, error)) {
+	// Decode a DirectorCorrectionRequestBody and apply the given
+	// correction to its game on behalf of the logged in director
+	// Guarantees:
+	// - Return the correction as it has been added to the
+	//   game's audit trail as JSON
+	// - Respond with HTTP 401 if no director is logged in
+
+	account, ok := getLoggedInAccount(responseWriter, request)
+	if !ok {
+		return
+	}
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody DirectorCorrectionRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	game, err := GetGameByUUID(requestBody.GameId)
+	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	correction, err := correct(game, *account, requestBody)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	correctionJson, err := json.Marshal(correction)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(correctionJson)
+}
+
+func CorrectScoreHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Set the points of the player on the seat PlayerIdx to Points
+	handleDirectorCorrection(responseWriter, request,
+		func(game *Game, director Account, requestBody DirectorCorrectionRequestBody) (DirectorCorrection, error) {
+			return game.CorrectScore(director, requestBody.PlayerIdx, requestBody.Points, requestBody.Reason)
+		})
+}
+
+func UndoLastTurnHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Take back the last finished turn of the game
+	handleDirectorCorrection(responseWriter, request,
+		func(game *Game, director Account, requestBody DirectorCorrectionRequestBody) (DirectorCorrection, error) {
+			return game.UndoLastTurn(director, requestBody.Reason)
+		})
+}
+
+func ReturnLetterToPlayerHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Hand a Letter from the letter set back to the player on the seat PlayerIdx
+	handleDirectorCorrection(responseWriter, request,
+		func(game *Game, director Account, requestBody DirectorCorrectionRequestBody) (DirectorCorrection, error) {
+			return game.ReturnLetterToPlayer(director, requestBody.PlayerIdx, requestBody.Letter, requestBody.Reason)
+		})
+}
+
+func AwardForfeitHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// End the game with a forfeit of the player on the seat PlayerIdx
+	handleDirectorCorrection(responseWriter, request,
+		func(game *Game, director Account, requestBody DirectorCorrectionRequestBody) (DirectorCorrection, error) {
+			return game.AwardForfeit(director, requestBody.PlayerIdx, requestBody.Reason)
+		})
+}
+
+func GetCorrectionsHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Get the audit trail of all corrections a director has made to a game
+	// Requires:
+	// - An incoming GET request with an ID in the request Path
+	// Guarantees:
+	// - Return a JSON list of DirectorCorrection structs, oldest first
+
+	id := mux.Vars(request)["id"]
+
+	game, err := GetReadableGameByUUID(id)
+	if err != nil {
+		log.Println("Not a valid GameID: ", id)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	correctionsJson, err := json.Marshal(game.Corrections)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(correctionsJson)
+}
+
+func SubmitDuplicateMoveHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Submit the move of a player for the current round of a duplicate game
+	// Requires:
+	// - An incoming HTTP Request Body with values to all keys
+	//   as they are defined in the SubmitDuplicateMoveRequestBody struct
+	// - The token of the player in the Authorization header
+	// Guarantees:
+	// - Return the scored DuplicateSubmission as JSON
+	// - HTTP 401 if the request could not be authenticated as a player
+	// - HTTP 500 and the error message if the move was rejected
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody SubmitDuplicateMoveRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	game, err := GetGameByUUID(requestBody.GameId)
+	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	participant, err := game.GetParticipantForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+	if participant.IsSpectator() {
+		http.Error(responseWriter, "Spectators cannot submit moves.", 401)
+		return
+	}
+
+	submission, err := game.SubmitDuplicateMove(participant.PlayerIdx, requestBody.Placements)
+	if err != nil {
+		writeMoveError(responseWriter, err, 500)
+		return
+	}
+
+	submissionJson, err := json.Marshal(submission)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(submissionJson)
+}
+
+func GetDuplicateRoundsHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Get all rounds of a duplicate game
+	// Requires:
+	// - An incoming GET request with an ID in the request Path
+	// - Optionally the token of a player in the Authorization header
+	//   to see the player's own submission for the running round
+	// Guarantees:
+	// - Return a JSON list of DuplicateRound structs, the current round last
+
+	id := mux.Vars(request)["id"]
+
+	game, err := GetReadableGameByUUID(id)
+	if err != nil {
+		log.Println("Not a valid GameID: ", id)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	viewer, err := game.GetParticipantForRequest(request)
+	if err != nil {
+		viewer = nil
+	}
+
+	rounds, err := game.GetDuplicateRounds(viewer)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	roundsJson, err := json.Marshal(rounds)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(roundsJson)
+}
+
+func ChallengeHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Challenge the last move of a game played with a challenge rule
+	// Requires:
+	// - GameId in Request Body
+	// - The token of a player other than the one who has played
+	//   the move in the Authorization header
+	// Guarantees:
+	// - Return the ChallengeRecord as JSON, whose PhonyWords are empty
+	//   if the challenge has failed
+	// - HTTP 401 if the request could not be authenticated as a player
+	// - HTTP 500 and the error message if the move can not be challenged
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody ChallengeRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	game, err := GetGameByUUID(requestBody.GameId)
+	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	participant, err := game.GetParticipantForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+	if participant.IsSpectator() {
+		http.Error(responseWriter, "Spectators cannot challenge moves.", 401)
+		return
+	}
+
+	challenge, err := game.ChallengeLastMove(participant.PlayerIdx)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	challengeJson, err := json.Marshal(challenge)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(challengeJson)
 }
 
 func StartWebServer() {
 	r := mux.NewRouter()
+	r.HandleFunc("/tournaments", GetTournamentsHandler).Methods("GET")
+	r.HandleFunc("/tournaments", CreateTournamentHandler).Methods("POST")
+	r.HandleFunc("/tournaments/register", RegisterForTournamentHandler).Methods("POST")
+	r.HandleFunc("/tournaments/start", StartTournamentHandler).Methods("POST")
+	r.HandleFunc("/tournaments/forfeit", ForfeitTournamentGameHandler).Methods("POST")
+	r.HandleFunc("/tournaments/{id}/pairings.json", GetTournamentPairingsHandler).Methods("GET")
+	r.HandleFunc("/tournaments/{id}/standings.json", GetTournamentStandingsHandler).Methods("GET")
 	r.HandleFunc("/new", CreateNewGameHandler).Methods("POST")
+	r.HandleFunc("/games", GetGamesHandler).Methods("GET")
 	r.HandleFunc("/{id}/board.json", GetBoardHandler).Methods("GET")
+	r.HandleFunc("/{id}/events", GetGameEventsHandler).Methods("GET")
 	r.HandleFunc("/{id}/player.json", GetActivePlayerHandler).Methods("GET")
 	r.HandleFunc("/{id}/potentialPoints.json", GetPotentialPointsHandler).Methods("GET")
+	r.HandleFunc("/{id}/unseen.json", GetUnseenLettersHandler).Methods("GET")
 	r.HandleFunc("/wildcard/replace", ReplaceWildcardHandler).Methods("POST")
 	r.HandleFunc("/hand/sort", SortHandHandler).Methods("POST")
 	r.HandleFunc("/place", PlaceLetterHandler).Methods("POST")
 	r.HandleFunc("/remove", RemoveLetterHandler).Methods("POST")
+	r.HandleFunc("/recall", RecallLettersHandler).Methods("POST")
+	r.HandleFunc("/relocate", MoveLetterHandler).Methods("POST")
+	r.HandleFunc("/swap", SwapLetterHandler).Methods("POST")
 	r.HandleFunc("/confirm", ConfirmWordHandler).Methods("POST")
+	r.HandleFunc("/move", PlayMoveHandler).Methods("POST")
+	r.HandleFunc("/move/validate", ValidateMoveHandler).Methods("POST")
+	r.HandleFunc("/resign", ResignHandler).Methods("POST")
+	r.HandleFunc("/undo/request", RequestUndoHandler).Methods("POST")
+	r.HandleFunc("/undo/approve", ApproveUndoHandler).Methods("POST")
+	r.HandleFunc("/undo/decline", DeclineUndoHandler).Methods("POST")
+	r.HandleFunc("/challenge", ChallengeHandler).Methods("POST")
 	r.HandleFunc("/{id}/scoreboard.json", GetScoreBoardHandler).Methods("GET")
-	log.Fatal(http.ListenAndServe(":8000", handlers.CORS()(r)))
+	r.HandleFunc("/join", JoinGameHandler).Methods("POST")
+	r.HandleFunc("/chat/post", PostChatMessageHandler).Methods("POST")
+	r.HandleFunc("/chat/mute", MuteParticipantHandler).Methods("POST")
+	r.HandleFunc("/{id}/chat.json", GetChatHistoryHandler).Methods("GET")
+	r.HandleFunc("/accounts/register", RegisterAccountHandler).Methods("POST")
+	r.HandleFunc("/accounts/login", LoginHandler).Methods("POST")
+	r.HandleFunc("/accounts/logout", LogoutHandler).Methods("POST")
+	r.HandleFunc("/players/{id}/rating.json", GetPlayerRatingHandler).Methods("GET")
+	r.HandleFunc("/players/{id}/statistics.json", GetPlayerStatisticsHandler).Methods("GET")
+	r.HandleFunc("/players/{id}/statistics.csv", GetPlayerStatisticsCSVHandler).Methods("GET")
+	r.HandleFunc("/{id}/history.json", GetGameHistoryHandler).Methods("GET")
+	r.HandleFunc("/{id}/bag.json", GetBagProofHandler).Methods("GET")
+	r.HandleFunc("/{id}/bag/verify", VerifyBagHandler).Methods("GET")
+	r.HandleFunc("/director/score", CorrectScoreHandler).Methods("POST")
+	r.HandleFunc("/director/undo", UndoLastTurnHandler).Methods("POST")
+	r.HandleFunc("/director/tile", ReturnLetterToPlayerHandler).Methods("POST")
+	r.HandleFunc("/director/forfeit", AwardForfeitHandler).Methods("POST")
+	r.HandleFunc("/{id}/corrections.json", GetCorrectionsHandler).Methods("GET")
+	r.HandleFunc("/duplicate/submit", SubmitDuplicateMoveHandler).Methods("POST")
+	r.HandleFunc("/{id}/duplicate.json", GetDuplicateRoundsHandler).Methods("GET")
+	log.Fatal(http.ListenAndServe(":8000", handlers.CORS(
+		handlers.AllowedHeaders([]string{"Authorization", "Content-Type", "Last-Event-ID"}),
+		handlers.ExposedHeaders([]string{GAME_VERSION_HEADER, PLACEMENT_ERROR_HEADER}),
+	)(r)))
+}
+
+func ResignHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Let a player leave a running game for good
+	// Requires:
+	// - GameId in Request Body
+	// - The token of a player in the Authorization header.
+	//   In team games either member resigns for the whole team.
+	// Guarantees:
+	// - HTTP 200 if the player has resigned
+	// - HTTP 401 if the request could not be authenticated as a player
+	// - HTTP 500 and the error message if the player cannot resign
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody ResignRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	game, err := GetGameByUUID(requestBody.GameId)
+	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	participant, err := game.GetParticipantForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+	if participant.IsSpectator() {
+		http.Error(responseWriter, "Spectators cannot resign.", 401)
+		return
+	}
+
+	err = game.ResignPlayer(participant.PlayerIdx, RESIGN_REASON_RESIGNED)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+}
+
+func handleUndo(responseWriter http.ResponseWriter, request *http.Request,
+	handleRequest func(game *Game, playerIdx int) error) {
+	// Shared handling of requests around taking back a move
+	// Requires:
+	// - GameId in Request Body
+	// - The token of a player in the Authorization header
+	// Guarantees:
+	// - HTTP 200 if the request has been handled
+	// - HTTP 401 if the request could not be authenticated as a player
+	// - HTTP 500 and the error message if the request cannot be handled
+
+	requestBodyDecoder := json.NewDecoder(request.Body)
+	var requestBody UndoRequestBody
+	err := requestBodyDecoder.Decode(&requestBody)
+	if err != nil {
+		http.Error(responseWriter, "Invalid body", 500)
+		return
+	}
+
+	game, err := GetGameByUUID(requestBody.GameId)
+	if err != nil {
+		log.Println("Not a valid GameID: ", requestBody.GameId)
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	game.Acquire()
+	defer game.Release()
+
+	participant, err := game.GetParticipantForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+	if participant.IsSpectator() {
+		http.Error(responseWriter, "Spectators cannot take part in undoing moves.", 401)
+		return
+	}
+
+	err = handleRequest(game, participant.PlayerIdx)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+}
+
+func RequestUndoHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Ask the opponents to agree to take back the player's last move
+	handleUndo(responseWriter, request, func(game *Game, playerIdx int) error {
+		return game.RequestUndo(playerIdx)
+	})
+}
+
+func ApproveUndoHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Agree to take back the move of the open undo request
+	handleUndo(responseWriter, request, func(game *Game, playerIdx int) error {
+		return game.ApproveUndo(playerIdx)
+	})
+}
+
+func DeclineUndoHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Refuse to take back the move of the open undo request
+	handleUndo(responseWriter, request, func(game *Game, playerIdx int) error {
+		return game.DeclineUndo(playerIdx)
+	})
+}
+
+func parseDateParameter(value string, isEndOfRange bool) (time.Time, error) {
+	// Accept full RFC 3339 timestamps as well as plain dates.
+	// A plain date at the end of a range includes the whole day.
+	parsedTime, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return parsedTime, nil
+	}
+	parsedTime, err = time.Parse("2006-01-02", value)
+	if err == nil && isEndOfRange {
+		parsedTime = parsedTime.Add(24*time.Hour - time.Nanosecond)
+	}
+	return parsedTime, err
+}
+
+func GetGamesHandler(responseWriter http.ResponseWriter, request *http.Request) {
+	// Find games without knowing their Id
+	// Requires:
+	// - Optionally the query parameters
+	//   -- "player": name or account Id of a player of the game
+	//   -- "status": one of lobby, active and finished
+	//   -- "from" and "to": range of the games' creation dates,
+	//      either as RFC 3339 timestamps or as plain dates (2006-01-02)
+	//   -- "word": a word that has been played in a finished game
+	//   -- "offset" and "limit" for pagination
+	// - Optionally the session token of an account in the Authorization header
+	// Guarantees:
+	// - Return a GameListPage as JSON, the most recently active games first
+	// - Games that are not finished yet are only listed for
+	//   the accounts seated in them, see GameListFilter
+	// - Guest games that are not finished yet are never listed,
+	//   guests have to keep the Ids of their running games themselves
+	// - HTTP 400 if a query parameter is invalid
+	// - HTTP 401 if a token is sent that is not a valid session token
+
+	viewer, err := GetAccountForRequest(request)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 401)
+		return
+	}
+
+	query := request.URL.Query()
+	filter := GameListFilter{
+		Player:     query.Get("player"),
+		Status:     query.Get("status"),
+		PlayedWord: query.Get("word"),
+	}
+	if viewer != nil {
+		filter.ViewerAccountId = viewer.Id
+	}
+
+	if fromParameter := query.Get("from"); fromParameter != "" {
+		filter.CreatedAfter, err = parseDateParameter(fromParameter, false)
+		if err != nil {
+			http.Error(responseWriter, "Invalid from parameter", 400)
+			return
+		}
+	}
+	if toParameter := query.Get("to"); toParameter != "" {
+		filter.CreatedBefore, err = parseDateParameter(toParameter, true)
+		if err != nil {
+			http.Error(responseWriter, "Invalid to parameter", 400)
+			return
+		}
+	}
+
+	var offset, limit int
+	if offsetParameter := query.Get("offset"); offsetParameter != "" {
+		offset, err = strconv.Atoi(offsetParameter)
+		if err != nil {
+			http.Error(responseWriter, "Invalid offset parameter", 400)
+			return
+		}
+	}
+	if limitParameter := query.Get("limit"); limitParameter != "" {
+		limit, err = strconv.Atoi(limitParameter)
+		if err != nil {
+			http.Error(responseWriter, "Invalid limit parameter", 400)
+			return
+		}
+	}
+
+	gameListPage, err := ListGames(filter, offset, limit)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 400)
+		return
+	}
+
+	gameListJson, err := json.Marshal(gameListPage)
+	if err != nil {
+		http.Error(responseWriter, err.Error(), 500)
+		return
+	}
+
+	responseWriter.Write(gameListJson)
 }